@@ -1,24 +1,110 @@
 package main
 
 import (
+	"github.com/MironCo/picopeeker/internal/bookmarks"
 	"github.com/MironCo/picopeeker/internal/config"
+	"github.com/MironCo/picopeeker/internal/firmware"
 	"github.com/MironCo/picopeeker/internal/serial"
+	"github.com/MironCo/picopeeker/internal/templates"
 	"github.com/MironCo/picopeeker/internal/ui"
 	"github.com/MironCo/picopeeker/internal/util"
+	"github.com/MironCo/picopeeker/internal/workspace"
+	"bytes"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
 	"fyne.io/fyne/v2/widget"
 )
 
-var landmarksLabel *widget.Label
-var currentModel config.PicoModel = config.Pico2
+// deviceWindow is a window bound to a single serial port
+type deviceWindow struct {
+	window      fyne.Window
+	portEntry   *widget.Entry
+	output      *widget.Entry
+	session     *serial.Session
+	connect     func()
+	projectPath string                        // Workspace file last opened or saved, if any
+	capture     func() *workspace.Workspace   // Snapshot of the window for a workspace
+	apply       func(ws *workspace.Workspace) // Restores a workspace
+	back        func()                        // Goes back in the Read tab's history
+	forward     func()                        // Goes forward in the Read tab's history
+}
+
+// deviceWindows tracks the open windows so a device is only bound once
+var deviceWindows []*deviceWindow
+
+var deviceManagerWindow fyne.Window
+var refreshDeviceManager func([]util.PortInfo)
+var picoPorts []util.PortInfo
 
 func main() {
-	myApp := app.New()
-	myWindow := myApp.NewWindow("PicoPeeker")
+	myApp := app.NewWithID("io.github.mironco.picopeeker") // The ID lets preferences persist
+	prefs := myApp.Preferences()
+
+	picoPorts, _ = util.FindPicoPorts()
+	defaultPort := startupPort(prefs)
+
+	myWindow := newDeviceWindow(myApp, serial.NewSession(defaultPort), startupModel(prefs))
+	myWindow.SetMaster()
+
+	// Keep the device list current as boards are plugged in and removed
+	stopWatching := util.WatchPicoPorts(time.Second, func(ports []util.PortInfo) {
+		fyne.Do(func() {
+			onPortsChanged(ports)
+		})
+	})
+	defer stopWatching()
+
+	myWindow.ShowAndRun()
+}
+
+// onPortsChanged runs on the UI thread whenever the set of attached Picos changes
+func onPortsChanged(ports []util.PortInfo) {
+	picoPorts = ports
+
+	if refreshDeviceManager != nil {
+		refreshDeviceManager(ports)
+	}
+
+	// Fill in windows that were opened before any board was attached
+	if len(ports) > 0 {
+		for _, dw := range deviceWindows {
+			if dw.portEntry.Text == "" {
+				dw.portEntry.SetText(ports[0].Name)
+				dw.connect()
+			}
+		}
+	}
+}
+
+// findDeviceWindow returns the window bound to the port, or nil
+func findDeviceWindow(portName string) *deviceWindow {
+	for _, dw := range deviceWindows {
+		if dw.session.Port() == portName {
+			return dw
+		}
+	}
+	return nil
+}
+
+// newDeviceWindow creates a window bound to a single serial session. Each window
+// has its own model, landmarks and output, so several Picos can be inspected at once.
+func newDeviceWindow(myApp fyne.App, session *serial.Session, model config.PicoModel) fyne.Window {
+	defaultPort := session.Port()
+	currentModel := model
+	currentRegions := config.GetMemoryRegions(model)
+
+	myWindow := myApp.NewWindow(fmt.Sprintf("PicoPeeker - %s", defaultPort))
 
 	// Output entry - monospace, read-only, but selectable for copy-paste
 	output := widget.NewMultiLineEntry()
@@ -27,36 +113,238 @@ func main() {
 
 	// Port input (shared across tabs)
 	portEntry := widget.NewEntry()
-	defaultPort := util.FindUSBModemPort()
-	portEntry.SetPlaceHolder(defaultPort)
+	portEntry.SetPlaceHolder("No Pico found - plug one in or enter a port")
 	portEntry.SetText(defaultPort)
+	if session.IsReplay() {
+		portEntry.Disable() // Replays aren't bound to a port
+	}
+
+	// One persistent connection per window, reconnecting on resets and cable wiggles
+	portEntry.OnChanged = func(port string) {
+		myWindow.SetTitle(fmt.Sprintf("PicoPeeker - %s", port))
+		session.SetPort(port)
+	}
+
+	// Connection status indicator
+	statusLabel := widget.NewLabel("")
+	setStatus := func(state serial.ConnState, portName string) {
+		switch state {
+		case serial.Connected:
+			statusLabel.Importance = widget.SuccessImportance
+		case serial.Reconnecting:
+			statusLabel.Importance = widget.WarningImportance
+		default:
+			statusLabel.Importance = widget.MediumImportance
+		}
+		statusLabel.SetText(fmt.Sprintf("● %s", state))
+		if session.IsReplay() {
+			statusLabel.SetText("● Replaying")
+		}
+
+		// Follow the board if it came back under a different name
+		if portName != portEntry.Text {
+			portEntry.SetText(portName)
+		}
+	}
+	setStatus(session.State(), defaultPort)
+	session.OnStateChange(func(state serial.ConnState, portName string) {
+		fyne.Do(func() {
+			setStatus(state, portName)
+		})
+	})
 
-	// Pico model selector
-	modelSelect := widget.NewSelect([]string{"Pico 1 (RP2040)", "Pico 2 (RP2350)"}, func(selected string) {
-		currentModel = config.GetModelFromString(selected)
-		regions := config.GetMemoryRegions(currentModel)
-		output.SetText(fmt.Sprintf("Model changed to %s\nSRAM: %s | Flash: %s", selected, regions.SRAMSize, regions.FlashSize))
+	// Core architecture selector - RP2350 firmware can be built for ARM or RISC-V
+	currentArch := config.ArchARM
+	archSelect := widget.NewSelect([]string{config.GetArchString(config.ArchARM), config.GetArchString(config.ArchRISCV)}, func(selected string) {
+		currentArch = config.GetArchFromString(selected)
 	})
-	modelSelect.SetSelected("Pico 2 (RP2350)")
+	archSelect.SetSelected(config.GetArchString(currentArch))
+
+	// Boards from the boards file are offered next to the built-in models
+	boards, boardsErr := config.LoadBoards()
+	findBoard := func(name string) (config.Board, bool) {
+		for _, board := range boards {
+			if board.Name == name {
+				return board, true
+			}
+		}
+		return config.Board{}, false
+	}
+	modelOptions := []string{"Pico 1 (RP2040)", "Pico 2 (RP2350)"}
+	for _, board := range boards {
+		modelOptions = append(modelOptions, board.Name)
+	}
+	var regionsChanged func() // Set once the tabs exist
+
+	// Pico model selector - set automatically on connect, can be overridden by hand
+	modelSelect := widget.NewSelect(modelOptions, func(selected string) {
+		if board, ok := findBoard(selected); ok {
+			currentModel = board.Model()
+			currentRegions = board.MemoryRegions()
+		} else {
+			currentModel = config.GetModelFromString(selected)
+			currentRegions = config.GetMemoryRegions(currentModel)
+		}
+		output.SetText(fmt.Sprintf("Model changed to %s\nSRAM: %s | Flash: %s", selected, currentRegions.SRAMSize, currentRegions.FlashSize))
+		if regionsChanged != nil {
+			regionsChanged()
+		}
+
+		// RP2040 only has ARM cores
+		if currentModel == config.Pico1 {
+			archSelect.SetSelected(config.GetArchString(config.ArchARM))
+			archSelect.Disable()
+		} else {
+			archSelect.Enable()
+		}
+	})
+	modelSelect.SetSelected(config.GetModelString(currentModel))
+	if _, ok := findBoard(myApp.Preferences().String(prefModel)); ok {
+		modelSelect.SetSelected(myApp.Preferences().String(prefModel))
+	}
+	if boardsErr != nil {
+		output.SetText(fmt.Sprintf("Error in board definitions: %v", boardsErr))
+	}
 
 	// Landmarks label (shared across tabs)
-	landmarksLabel = widget.NewLabel("Landmarks: Not connected")
+	landmarksLabel := widget.NewLabel("Landmarks: Not connected")
 	landmarksLabel.TextStyle = fyne.TextStyle{Monospace: true}
 
-	// Connect button (shared)
-	connectBtn := widget.NewButton("Get Landmarks", func() {
-		portName := portEntry.Text
-		if portName == "" {
+	// Symbols from the firmware's ELF, used to annotate disassembly
+	var symbols *firmware.SymbolTable
+
+	// Landmarks from the last connect, which address expressions can refer to
+	var deviceLandmarks string
+
+	// Bookmarks follow the firmware: its ELF if loaded, else the address of main
+	var bookmarkPanel *ui.BookmarkPanel // Set once the output area exists
+
+	// connect identifies the device and fetches its landmarks in the background
+	connect := func() {
+		if session.Port() == "" {
 			output.SetText("Error: Please enter a port name")
 			return
 		}
+		landmarksLabel.SetText("Landmarks: Connecting...")
 
-		landmarks, err := serial.FetchLandmarks(portName)
-		if err != nil {
-			landmarksLabel.SetText(fmt.Sprintf("Landmarks: Could not connect - %v", err))
-		} else {
-			landmarksLabel.SetText(fmt.Sprintf("Landmarks: %s", landmarks))
+		go func() {
+			info, infoErr := session.FetchInfo()
+			landmarks, err := session.FetchLandmarks()
+
+			fyne.Do(func() {
+				if err != nil {
+					landmarksLabel.SetText(fmt.Sprintf("Landmarks: Could not connect - %v", err))
+					return
+				}
+				landmarksLabel.SetText(fmt.Sprintf("Landmarks: %s", landmarks))
+				deviceLandmarks = landmarks
+				if mainAddr, ok := serial.LandmarkAddress(landmarks, "main"); ok && symbols == nil {
+					bookmarkPanel.SetProject(bookmarks.LandmarkKey(mainAddr), fmt.Sprintf("Firmware with main @ 0x%08x", mainAddr))
+				}
+
+				if infoErr != nil {
+					output.SetText(fmt.Sprintf("Could not detect model (%v)\nSelect it manually if %s is wrong.", infoErr, config.GetModelString(currentModel)))
+					return
+				}
+
+				// A board from the boards file is kept if it has the detected chip, since
+				// its memory map is more specific than the detected sizes
+				board, custom := findBoard(modelSelect.Selected)
+				custom = custom && board.Model() == config.GetModelFromChip(info.Chip)
+				if !custom {
+					modelSelect.SetSelected(config.GetModelString(config.GetModelFromChip(info.Chip)))
+				}
+				if info.Arch != "" {
+					archSelect.SetSelected(config.GetArchString(config.GetArchFromInfo(info.Arch)))
+				}
+
+				// Selecting the model resets the regions to its defaults, so apply detected sizes after
+				if custom {
+					currentRegions = board.MemoryRegions()
+					info.FlashSizeSource = "boards"
+				} else {
+					currentRegions = config.GetDetectedMemoryRegions(currentModel, info.SRAMSize, info.FlashSize).WithPSRAM(info.PSRAMSize)
+				}
+				// The firmware's region table is what it will actually read, e.g. flash
+				// up to PICOPEEKER_FLASH_END rather than the whole chip JEDEC reports.
+				// The sizes above are only a fallback for firmware that has no table.
+				if len(info.Regions) > 0 {
+					currentRegions = currentRegions.WithReported(info.Regions)
+				}
+				regionsChanged()
+				output.SetText(formatDeviceInfo(info, currentRegions))
+			})
+		}()
+	}
+
+	// Connect button (shared)
+	connectBtn := widget.NewButton("Connect", connect)
+	dw := &deviceWindow{window: myWindow, portEntry: portEntry, output: output, session: session, connect: connect}
+	deviceWindows = append(deviceWindows, dw)
+
+	// A reset may have loaded different firmware, so identify the device again.
+	// That is all there is to restore: there are no watches (live reads of an
+	// address) to replay yet, and a watch feature would register its own
+	// OnReconnect callback to do that.
+	session.OnReconnect(func() {
+		fyne.Do(connect)
+	})
+
+	devicesBtn := widget.NewButton("Devices...", func() {
+		showDeviceManager(myApp)
+	})
+
+	// Session recording - captures every command and response for bug reports and replay
+	var recordBtn *widget.Button
+	recordBtn = widget.NewButton("Record", func() {
+		if !session.IsRecording() {
+			session.StartRecording()
+			recordBtn.SetText("Stop Recording")
+			recordBtn.Importance = widget.DangerImportance
+			recordBtn.Refresh()
+			return
 		}
+
+		rec := session.StopRecording()
+		recordBtn.SetText("Record")
+		recordBtn.Importance = widget.MediumImportance
+		recordBtn.Refresh()
+
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+			if err := rec.Write(writer); err != nil {
+				dialog.ShowError(err, myWindow)
+				return
+			}
+			output.SetText(fmt.Sprintf("Saved %d recorded command(s) to %s", len(rec.Exchanges), writer.URI().Path()))
+		}, myWindow)
+		saveDialog.SetFileName(fmt.Sprintf("picopeeker-session-%s.json", rec.Started.Format("20060102-150405")))
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+		saveDialog.Show()
+	})
+	if session.IsReplay() {
+		recordBtn.Disable()
+	}
+
+	replayBtn := widget.NewButton("Replay...", func() {
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			rec, err := serial.ReadRecording(reader)
+			if err != nil {
+				dialog.ShowError(err, myWindow)
+				return
+			}
+			newDeviceWindow(myApp, serial.NewReplaySession(rec), currentModel).Show()
+		}, myWindow)
+		openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".json"}))
+		openDialog.Show()
 	})
 
 	// Channel for background operations
@@ -73,26 +361,239 @@ func main() {
 		}
 	}()
 
-	// Build tabs - pass function to get current model
-	getModel := func() config.PicoModel { return currentModel }
-	readTab := ui.BuildReadMemoryTab(portEntry, output, updateChan, getModel)
-	searchTab := ui.BuildSearchMemoryTab(portEntry, output, updateChan, getModel)
+	var refreshSections func() // Set once the Search tab exists
+	var elfPath string         // Where the ELF was loaded from, for workspaces
+	loadELF := func(name, path string, data []byte) error {
+		table, err := firmware.LoadSymbols(bytes.NewReader(data))
+		if err != nil {
+			return err
+		}
+		symbols, elfPath = table, path
+		refreshSections()
+		bookmarkPanel.SetProject(bookmarks.ELFKey(data), name)
+		output.SetText(fmt.Sprintf("Loaded %d symbols from %s", table.Len(), name))
+		return nil
+	}
+	loadELFBtn := widget.NewButton("Load ELF...", func() {
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			if err == nil {
+				err = loadELF(reader.URI().Name(), reader.URI().Path(), data)
+			}
+			if err != nil {
+				dialog.ShowError(err, myWindow)
+			}
+		}, myWindow)
+		openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".elf", ".axf"}))
+		openDialog.Show()
+	})
+
+	// Build tabs - they share the session, output and current memory regions
+	outputArea := ui.NewOutputArea(output)
+	ctx := &ui.DeviceContext{
+		Window:      myWindow,
+		Session:     session,
+		Output:      output,
+		UpdateChan:  updateChan,
+		Regions:     func() config.MemoryRegions { return currentRegions },
+		Arch:        func() config.CoreArch { return currentArch },
+		Symbols:     func() *firmware.SymbolTable { return symbols },
+		Landmarks:   func() string { return deviceLandmarks },
+		Disassembly: ui.NewDisassemblyView(outputArea),
+	}
+	ctx.HexView = ui.NewHexView(ctx, outputArea)
+	regionsChanged = ctx.RegionsChanged
+	bookmarkPanel = ui.NewBookmarkPanel(ctx)
+	ctx.Bookmarks = bookmarkPanel
+	readTab := ui.BuildReadMemoryTab(ctx)
+	readTab.Apply(savedReadSettings(myApp.Preferences()))
+	var searchTab *container.TabItem
+	searchTab, refreshSections = ui.BuildSearchMemoryTab(ctx)
+	stringsTab := ui.BuildStringsTab(ctx)
+	pointerTab := ui.BuildPointerScanTab(ctx)
+	verifyTab := ui.BuildVerifyTab(ctx)
 
-	tabs := container.NewAppTabs(readTab, searchTab)
+	tabs := container.NewAppTabs(readTab.Item, searchTab, stringsTab, pointerTab, verifyTab)
+	ctx.Navigate = func(addr uint32) {
+		tabs.Select(readTab.Item)
+		readTab.ReadAt(addr)
+	}
+
+	bookmarksBtn := widget.NewButton("Bookmarks", bookmarkPanel.Toggle)
 
 	// Main layout
-	portRow := container.NewBorder(nil, nil, widget.NewLabel("Serial Port:"), connectBtn, portEntry)
-	modelRow := container.NewBorder(nil, nil, widget.NewLabel("Pico Model:"), nil, modelSelect)
+	portRow := container.NewBorder(nil, nil, widget.NewLabel("Serial Port:"), container.NewHBox(statusLabel, connectBtn, devicesBtn, recordBtn, replayBtn), portEntry)
+	modelRow := container.NewBorder(nil, nil, widget.NewLabel("Pico Model:"), container.NewHBox(widget.NewLabel("Cores:"), archSelect, loadELFBtn, bookmarksBtn), modelSelect)
 
 	content := container.NewBorder(
 		container.NewVBox(portRow, modelRow, tabs),
 		landmarksLabel,
 		nil,
-		nil,
-		container.NewScroll(output),
+		bookmarkPanel.Content(),
+		outputArea.Content(),
 	)
 
+	// Workspaces save and restore the window's state
+	dw.capture = func() *workspace.Workspace {
+		ws := &workspace.Workspace{
+			ELF:       elfPath,
+			Model:     modelSelect.Selected,
+			Read:      readTab.Settings(),
+			Bookmarks: bookmarkPanel.Bookmarks(),
+		}
+		if !session.IsReplay() {
+			ws.Port = session.Port()
+		}
+		if source, err := templates.LoadSource(); err == nil && source != templates.DefaultSource {
+			ws.Templates = source
+		}
+		size := myWindow.Canvas().Size()
+		ws.Layout = workspace.Layout{
+			Width:         size.Width,
+			Height:        size.Height,
+			ShowBookmarks: bookmarkPanel.Visible(),
+			HideInspector: !ctx.HexView.InspectorVisible(),
+		}
+		if tab := tabs.Selected(); tab != nil {
+			ws.Layout.Tab = tab.Text
+		}
+		return ws
+	}
+	dw.apply = func(ws *workspace.Workspace) {
+		var notes []string
+		if ws.Port != "" && ws.Port != portEntry.Text && !session.IsReplay() {
+			portEntry.SetText(ws.Port)
+			connect()
+		}
+		if ws.Model != "" {
+			modelSelect.SetSelected(ws.Model)
+		}
+		if ws.ELF != "" {
+			data, err := os.ReadFile(ws.ELF)
+			if err == nil {
+				err = loadELF(filepath.Base(ws.ELF), ws.ELF, data)
+			}
+			if err != nil {
+				notes = append(notes, fmt.Sprintf("Could not load %s: %v", ws.ELF, err))
+			}
+		}
+		if added := bookmarkPanel.Merge(ws.Bookmarks); added > 0 {
+			notes = append(notes, fmt.Sprintf("Added %d bookmark(s)", added))
+		}
+		if ws.Templates != "" {
+			if err := readTab.SetTemplates(ws.Templates); err != nil {
+				notes = append(notes, fmt.Sprintf("Could not use the project's struct templates: %v", err))
+			}
+		}
+		readTab.Apply(ws.Read)
+
+		if ws.Layout.Width > 0 && ws.Layout.Height > 0 {
+			myWindow.Resize(fyne.NewSize(ws.Layout.Width, ws.Layout.Height))
+		}
+		for _, tab := range tabs.Items {
+			if tab.Text == ws.Layout.Tab {
+				tabs.Select(tab)
+			}
+		}
+		bookmarkPanel.SetVisible(ws.Layout.ShowBookmarks)
+		ctx.HexView.SetInspectorVisible(!ws.Layout.HideInspector)
+
+		output.SetText(strings.Join(append([]string{fmt.Sprintf("Opened project %s", dw.projectPath)}, notes...), "\n"))
+	}
+	dw.back = func() {
+		tabs.Select(readTab.Item)
+		readTab.Back()
+	}
+	dw.forward = func() {
+		tabs.Select(readTab.Item)
+		readTab.Forward()
+	}
+	myWindow.SetMainMenu(buildMainMenu(myApp, dw))
+
+	myWindow.SetOnClosed(func() {
+		saveSettings(myApp.Preferences(), dw.capture())
+		deviceWindows = slices.DeleteFunc(deviceWindows, func(other *deviceWindow) bool {
+			return other == dw
+		})
+		session.Close()
+	})
+
 	myWindow.SetContent(content)
 	myWindow.Resize(fyne.NewSize(900, 850))
-	myWindow.ShowAndRun()
+
+	// Detect the model straight away if a board is already attached
+	if defaultPort != "" {
+		connect()
+	}
+	return myWindow
+}
+
+// formatDeviceInfo summarizes what was detected on connect
+func formatDeviceInfo(info serial.DeviceInfo, regions config.MemoryRegions) string {
+	flashSource := "from board definition"
+	switch info.FlashSizeSource {
+	case "jedec":
+		flashSource = "from flash JEDEC ID"
+	case "boards":
+		flashSource = "from the boards file"
+	}
+	// The firmware may read less of the chip than JEDEC reports
+	if info.FlashSize != 0 && regions.FlashSizeHex != info.FlashSize {
+		flashSource = fmt.Sprintf("readable by the firmware, of %s %s", config.FormatSize(info.FlashSize), flashSource)
+	}
+
+	arch := "unknown (set it by hand)"
+	if info.Arch != "" {
+		arch = config.GetArchString(config.GetArchFromInfo(info.Arch))
+	}
+
+	psram := ""
+	if info.PSRAMSize != 0 {
+		psram = " | PSRAM: " + config.FormatSize(info.PSRAMSize)
+	}
+
+	var names []string
+	for _, region := range regions.Map {
+		names = append(names, region.Name)
+	}
+
+	return fmt.Sprintf("Detected %s (CHIP_ID 0x%08x)\nCores: %s\nSDK: %s\nSRAM: %s | Flash: %s (%s)%s\nRegions: %s\nBoard ID: %s",
+		info.Chip, info.ChipID, arch, info.SDKVersion, regions.SRAMSize, regions.FlashSize, flashSource, psram, strings.Join(names, ", "), info.BoardID)
+}
+
+// showDeviceManager opens (or focuses) the window listing every attached Pico
+func showDeviceManager(myApp fyne.App) {
+	if deviceManagerWindow != nil {
+		deviceManagerWindow.RequestFocus()
+		return
+	}
+
+	deviceManagerWindow = myApp.NewWindow("PicoPeeker Devices")
+	isBound := func(portName string) bool {
+		return findDeviceWindow(portName) != nil
+	}
+	content, refresh := ui.BuildDeviceManager(isBound, func(device serial.Device) {
+		if existing := findDeviceWindow(device.Port); existing != nil {
+			existing.window.RequestFocus()
+			return
+		}
+
+		window := newDeviceWindow(myApp, serial.NewSession(device.Port), config.GetModelFromChip(device.Info.Chip))
+		window.Show()
+	})
+	refreshDeviceManager = refresh
+	refresh(picoPorts)
+
+	deviceManagerWindow.SetContent(content)
+	deviceManagerWindow.SetOnClosed(func() {
+		deviceManagerWindow = nil
+		refreshDeviceManager = nil
+	})
+	deviceManagerWindow.Resize(fyne.NewSize(700, 400))
+	deviceManagerWindow.Show()
 }
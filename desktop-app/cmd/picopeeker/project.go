@@ -0,0 +1,181 @@
+package main
+
+import (
+	"github.com/MironCo/picopeeker/internal/config"
+	"github.com/MironCo/picopeeker/internal/util"
+	"github.com/MironCo/picopeeker/internal/workspace"
+	"fmt"
+	"path/filepath"
+	"slices"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/storage"
+)
+
+// Preference keys. Settings are saved when a device window closes and
+// restored in the next window that opens.
+const (
+	prefPort           = "port"
+	prefModel          = "model"
+	prefReadAddress    = "read.address"
+	prefReadLength     = "read.length"
+	prefReadDisplay    = "read.display"
+	prefRecentProjects = "recent_projects"
+)
+
+// startupPort picks the port used last time if that board is attached, or else the first Pico found
+func startupPort(prefs fyne.Preferences) string {
+	last := prefs.String(prefPort)
+	if slices.ContainsFunc(picoPorts, func(port util.PortInfo) bool { return port.Name == last }) {
+		return last
+	}
+	return util.FindUSBModemPort()
+}
+
+// startupModel returns the model selected last time
+func startupModel(prefs fyne.Preferences) config.PicoModel {
+	return config.GetModelFromString(prefs.StringWithFallback(prefModel, config.GetModelString(config.Pico2)))
+}
+
+// savedReadSettings returns the Read tab settings from last time
+func savedReadSettings(prefs fyne.Preferences) workspace.Read {
+	return workspace.Read{
+		Address: prefs.String(prefReadAddress),
+		Length:  prefs.String(prefReadLength),
+		Display: prefs.String(prefReadDisplay),
+	}
+}
+
+// saveSettings remembers a window's state for the next launch
+func saveSettings(prefs fyne.Preferences, ws *workspace.Workspace) {
+	if ws.Port != "" {
+		prefs.SetString(prefPort, ws.Port)
+	}
+	prefs.SetString(prefModel, ws.Model)
+	prefs.SetString(prefReadAddress, ws.Read.Address)
+	prefs.SetString(prefReadLength, ws.Read.Length)
+	prefs.SetString(prefReadDisplay, ws.Read.Display)
+}
+
+// addRecentProject puts path at the top of the Recent Projects menu in every window
+func addRecentProject(myApp fyne.App, path string) {
+	prefs := myApp.Preferences()
+	prefs.SetStringList(prefRecentProjects, workspace.AddRecent(prefs.StringList(prefRecentProjects), path))
+	refreshMenus(myApp)
+}
+
+func refreshMenus(myApp fyne.App) {
+	for _, dw := range deviceWindows {
+		dw.window.SetMainMenu(buildMainMenu(myApp, dw))
+	}
+}
+
+// buildMainMenu creates a device window's File and Go menus
+func buildMainMenu(myApp fyne.App, dw *deviceWindow) *fyne.MainMenu {
+	prefs := myApp.Preferences()
+
+	var recentItems []*fyne.MenuItem
+	for _, path := range prefs.StringList(prefRecentProjects) {
+		recentItems = append(recentItems, fyne.NewMenuItem(path, func() {
+			openProject(myApp, dw, path)
+		}))
+	}
+	if len(recentItems) == 0 {
+		none := fyne.NewMenuItem("(none)", nil)
+		none.Disabled = true
+		recentItems = append(recentItems, none)
+	} else {
+		recentItems = append(recentItems, fyne.NewMenuItemSeparator(), fyne.NewMenuItem("Clear Recent Projects", func() {
+			prefs.SetStringList(prefRecentProjects, nil)
+			refreshMenus(myApp)
+		}))
+	}
+	recent := fyne.NewMenuItem("Recent Projects", nil)
+	recent.ChildMenu = fyne.NewMenu("", recentItems...)
+
+	file := fyne.NewMenu("File",
+		fyne.NewMenuItem("Open Project...", func() {
+			showOpenProject(myApp, dw)
+		}),
+		recent,
+		fyne.NewMenuItemSeparator(),
+		fyne.NewMenuItem("Save Project", func() {
+			if dw.projectPath == "" {
+				showSaveProject(myApp, dw)
+				return
+			}
+			saveProject(myApp, dw, dw.projectPath)
+		}),
+		fyne.NewMenuItem("Save Project As...", func() {
+			showSaveProject(myApp, dw)
+		}),
+	)
+
+	// Menu shortcuts work even while an entry has focus
+	back := fyne.NewMenuItem("Back", dw.back)
+	back.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyLeft, Modifier: fyne.KeyModifierAlt}
+	forward := fyne.NewMenuItem("Forward", dw.forward)
+	forward.Shortcut = &desktop.CustomShortcut{KeyName: fyne.KeyRight, Modifier: fyne.KeyModifierAlt}
+	goMenu := fyne.NewMenu("Go", back, forward)
+
+	return fyne.NewMainMenu(file, goMenu)
+}
+
+func showOpenProject(myApp fyne.App, dw *deviceWindow) {
+	openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+		if err != nil || reader == nil {
+			return
+		}
+		reader.Close()
+		openProject(myApp, dw, reader.URI().Path())
+	}, dw.window)
+	openDialog.SetFilter(storage.NewExtensionFileFilter([]string{workspace.Extension}))
+	openDialog.Show()
+}
+
+// openProject restores a workspace into the window
+func openProject(myApp fyne.App, dw *deviceWindow, path string) {
+	ws, err := workspace.Load(path)
+	if err != nil {
+		dialog.ShowError(err, dw.window)
+		return
+	}
+	dw.projectPath = path
+	dw.apply(ws)
+	addRecentProject(myApp, path)
+}
+
+func showSaveProject(myApp fyne.App, dw *deviceWindow) {
+	saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+		if err != nil || writer == nil {
+			return
+		}
+		defer writer.Close()
+		ws := dw.capture()
+		if err := ws.Write(writer); err != nil {
+			dialog.ShowError(err, dw.window)
+			return
+		}
+		dw.projectPath = writer.URI().Path()
+		dw.output.SetText(fmt.Sprintf("Saved project to %s", dw.projectPath))
+		addRecentProject(myApp, dw.projectPath)
+	}, dw.window)
+	name := "project" + workspace.Extension
+	if dw.projectPath != "" {
+		name = filepath.Base(dw.projectPath)
+	}
+	saveDialog.SetFileName(name)
+	saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{workspace.Extension}))
+	saveDialog.Show()
+}
+
+func saveProject(myApp fyne.App, dw *deviceWindow, path string) {
+	if err := dw.capture().Save(path); err != nil {
+		dialog.ShowError(err, dw.window)
+		return
+	}
+	dw.output.SetText(fmt.Sprintf("Saved project to %s", path))
+	addRecentProject(myApp, path)
+}
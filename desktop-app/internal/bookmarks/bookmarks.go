@@ -0,0 +1,249 @@
+package bookmarks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Types are suggested for new bookmarks; any other text is allowed too
+var Types = []string{"data", "code", "u8", "u16", "u32", "u64", "float", "double", "string", "pointer", "struct"}
+
+// DefaultKey holds bookmarks made before the firmware has been identified
+const DefaultKey = "default"
+
+// Bookmark names an address range
+type Bookmark struct {
+	Name    string
+	Address uint32
+	Length  uint32 // At least 1
+	Type    string
+	Comment string
+}
+
+// End returns the address just past the bookmark. It is 64-bit so that a
+// bookmark ending at 0xFFFFFFFF doesn't wrap to 0.
+func (b Bookmark) End() uint64 {
+	return uint64(b.Address) + uint64(max(b.Length, 1))
+}
+
+// Contains reports whether addr is inside the bookmark
+func (b Bookmark) Contains(addr uint32) bool {
+	return addr >= b.Address && uint64(addr) < b.End()
+}
+
+// Project is the set of bookmarks for one firmware image
+type Project struct {
+	Key       string
+	Name      string // Describes the firmware, e.g. the ELF's file name
+	Bookmarks []Bookmark
+}
+
+// ELFKey identifies a project by the contents of its ELF file
+func ELFKey(data []byte) string {
+	sum := sha256.Sum256(data)
+	return "elf-" + hex.EncodeToString(sum[:8])
+}
+
+// LandmarkKey identifies a project by the address of main reported by the
+// firmware, for when no ELF has been loaded
+func LandmarkKey(mainAddr uint32) string {
+	return fmt.Sprintf("main-%08x", mainAddr)
+}
+
+// Dir returns where projects are stored
+func Dir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("no config directory: %w", err)
+	}
+	return filepath.Join(dir, "picopeeker", "bookmarks"), nil
+}
+
+// Path returns the file a project is stored in
+func Path(key string) (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, key+".json"), nil
+}
+
+// fileBookmark keeps the address in hex so files can be edited by hand
+type fileBookmark struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+	Length  uint32 `json:"length"`
+	Type    string `json:"type,omitempty"`
+	Comment string `json:"comment,omitempty"`
+}
+
+// MarshalJSON writes the address in hex
+func (b Bookmark) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fileBookmark{
+		Name:    b.Name,
+		Address: fmt.Sprintf("0x%08x", b.Address),
+		Length:  b.Length,
+		Type:    b.Type,
+		Comment: b.Comment,
+	})
+}
+
+// UnmarshalJSON reads a bookmark written by MarshalJSON
+func (b *Bookmark) UnmarshalJSON(data []byte) error {
+	var fb fileBookmark
+	if err := json.Unmarshal(data, &fb); err != nil {
+		return err
+	}
+	addr, err := strconv.ParseUint(strings.TrimSpace(fb.Address), 0, 32)
+	if err != nil {
+		return fmt.Errorf("bookmark %q: invalid address %q", fb.Name, fb.Address)
+	}
+	*b = Bookmark{Name: fb.Name, Address: uint32(addr), Length: max(fb.Length, 1), Type: fb.Type, Comment: fb.Comment}
+	return nil
+}
+
+type projectFile struct {
+	Name      string     `json:"name"`
+	Bookmarks []Bookmark `json:"bookmarks"`
+}
+
+// Load reads a project, or returns an empty one if it has never been saved.
+// name replaces the saved description unless it is empty.
+func Load(key, name string) (*Project, error) {
+	project := &Project{Key: key, Name: name}
+	path, err := Path(key)
+	if err != nil {
+		return project, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return project, nil
+	}
+	if err != nil {
+		return project, fmt.Errorf("failed to read bookmarks: %w", err)
+	}
+
+	var file projectFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return project, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if project.Name == "" {
+		project.Name = file.Name
+	}
+	project.Bookmarks = file.Bookmarks
+	project.sort()
+	return project, nil
+}
+
+// Save writes the project to its file
+func (p *Project) Save() error {
+	file := projectFile{Name: p.Name, Bookmarks: p.Bookmarks}
+	if file.Bookmarks == nil {
+		file.Bookmarks = []Bookmark{}
+	}
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path, err := Path(p.Key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save bookmarks: %w", err)
+	}
+	return nil
+}
+
+// Add inserts a bookmark, keeping them sorted by address
+func (p *Project) Add(b Bookmark) {
+	b.Length = max(b.Length, 1)
+	p.Bookmarks = append(p.Bookmarks, b)
+	p.sort()
+}
+
+// Replace swaps the bookmark at index i for b
+func (p *Project) Replace(i int, b Bookmark) {
+	b.Length = max(b.Length, 1)
+	p.Bookmarks[i] = b
+	p.sort()
+}
+
+// Merge adds the bookmarks that aren't already in the project (by name and
+// address) and returns how many were added
+func (p *Project) Merge(bookmarks []Bookmark) int {
+	added := 0
+	for _, b := range bookmarks {
+		exists := slices.ContainsFunc(p.Bookmarks, func(existing Bookmark) bool {
+			return existing.Name == b.Name && existing.Address == b.Address
+		})
+		if !exists {
+			p.Bookmarks = append(p.Bookmarks, b)
+			added++
+		}
+	}
+	p.sort()
+	return added
+}
+
+// Remove deletes the bookmark at index i
+func (p *Project) Remove(i int) {
+	p.Bookmarks = append(p.Bookmarks[:i], p.Bookmarks[i+1:]...)
+}
+
+// At returns the bookmark covering addr. When bookmarks nest, the smallest wins.
+func (p *Project) At(addr uint32) (Bookmark, bool) {
+	var found Bookmark
+	ok := false
+	for _, b := range p.Bookmarks {
+		if b.Address > addr {
+			break
+		}
+		if b.Contains(addr) && (!ok || b.Length < found.Length) {
+			found, ok = b, true
+		}
+	}
+	return found, ok
+}
+
+// Find returns the bookmark with the given name
+func (p *Project) Find(name string) (Bookmark, bool) {
+	for _, b := range p.Bookmarks {
+		if b.Name == name {
+			return b, true
+		}
+	}
+	return Bookmark{}, false
+}
+
+// StartingIn returns the bookmarks that start in [start, end)
+func (p *Project) StartingIn(start, end uint32) []Bookmark {
+	i := sort.Search(len(p.Bookmarks), func(i int) bool {
+		return p.Bookmarks[i].Address >= start
+	})
+	var found []Bookmark
+	for ; i < len(p.Bookmarks) && p.Bookmarks[i].Address < end; i++ {
+		found = append(found, p.Bookmarks[i])
+	}
+	return found
+}
+
+func (p *Project) sort() {
+	sort.SliceStable(p.Bookmarks, func(i, j int) bool {
+		return p.Bookmarks[i].Address < p.Bookmarks[j].Address
+	})
+}
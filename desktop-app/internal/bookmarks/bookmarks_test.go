@@ -0,0 +1,41 @@
+package bookmarks
+
+import "testing"
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		b    Bookmark
+		addr uint32
+		want bool
+	}{
+		{Bookmark{Address: 0x20000000, Length: 16}, 0x20000000, true},
+		{Bookmark{Address: 0x20000000, Length: 16}, 0x2000000f, true},
+		{Bookmark{Address: 0x20000000, Length: 16}, 0x20000010, false},
+		{Bookmark{Address: 0x20000000, Length: 16}, 0x1fffffff, false},
+		{Bookmark{Address: 0x20000000}, 0x20000000, true}, // Length 0 counts as 1
+		// Ranges that end at the top of the address space
+		{Bookmark{Address: 0xfffffff0, Length: 16}, 0xffffffff, true},
+		{Bookmark{Address: 0xfffffff0, Length: 16}, 0xfffffff0, true},
+		{Bookmark{Address: 0xfffffff0, Length: 16}, 0x00000000, false},
+		{Bookmark{Address: 0xffffffff, Length: 1}, 0xffffffff, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.b.Contains(tt.addr); got != tt.want {
+			t.Errorf("%+v Contains(0x%08x) = %v, want %v", tt.b, tt.addr, got, tt.want)
+		}
+	}
+}
+
+func TestAtEndOfAddressSpace(t *testing.T) {
+	p := &Project{}
+	p.Add(Bookmark{Name: "top", Address: 0xffffff00, Length: 0x100})
+	p.Add(Bookmark{Name: "last word", Address: 0xfffffffc, Length: 4})
+
+	if b, ok := p.At(0xfffffffe); !ok || b.Name != "last word" {
+		t.Errorf("At(0xfffffffe) = %+v, %v, want the smaller bookmark", b, ok)
+	}
+	if b, ok := p.At(0xffffff10); !ok || b.Name != "top" {
+		t.Errorf("At(0xffffff10) = %+v, %v, want top", b, ok)
+	}
+}
@@ -0,0 +1,221 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Access flags of a memory region
+type Access uint8
+
+const (
+	AccessRead Access = 1 << iota
+	AccessWrite
+	AccessExec
+)
+
+// ParseAccess converts flags such as "rx" or "rw" to Access
+func ParseAccess(flags string) (Access, error) {
+	var access Access
+	for _, flag := range strings.ToLower(flags) {
+		switch flag {
+		case 'r':
+			access |= AccessRead
+		case 'w':
+			access |= AccessWrite
+		case 'x':
+			access |= AccessExec
+		case '-':
+		default:
+			return 0, fmt.Errorf("unknown access flag %q in %q (use r, w and x)", flag, flags)
+		}
+	}
+	return access, nil
+}
+
+// String returns the flags as "rwx", with dashes for missing ones
+func (a Access) String() string {
+	flags := []byte("---")
+	if a&AccessRead != 0 {
+		flags[0] = 'r'
+	}
+	if a&AccessWrite != 0 {
+		flags[1] = 'w'
+	}
+	if a&AccessExec != 0 {
+		flags[2] = 'x'
+	}
+	return string(flags)
+}
+
+// Region is a named range of the address space
+type Region struct {
+	Name     string
+	Start    uint32
+	End      uint32 // Exclusive
+	Access   Access
+	AliasOf  string // Set for mirrors of another region, e.g. the XIP non-cached view of flash
+	NoSearch bool   // Left out of quick access and region pickers, e.g. peripherals, where reads have side effects
+}
+
+// Size returns the region's length in bytes
+func (r Region) Size() uint32 {
+	return r.End - r.Start
+}
+
+// Contains reports whether addr is inside the region
+func (r Region) Contains(addr uint32) bool {
+	return addr >= r.Start && addr < r.End
+}
+
+// Board is a memory map loaded from the boards file
+type Board struct {
+	Name    string
+	Chip    string // "RP2040" or "RP2350"
+	Regions []Region
+}
+
+// Model returns the Pico model with the board's chip
+func (b Board) Model() PicoModel {
+	return GetModelFromChip(b.Chip)
+}
+
+// MemoryRegions returns the board's memory map. Its Flash and SRAM regions
+// set the sizes used across the app.
+func (b Board) MemoryRegions() MemoryRegions {
+	regions := MemoryRegions{Map: b.Regions}
+	if flash, ok := regions.Find("Flash"); ok {
+		regions.FlashSizeHex, regions.FlashSize = flash.Size(), FormatSize(flash.Size())
+	}
+	if sram, ok := regions.Find("SRAM"); ok {
+		regions.SRAMSizeHex, regions.SRAMSize = sram.Size(), FormatSize(sram.Size())
+	}
+	return regions
+}
+
+// BoardsPath returns where board definitions are read from
+func BoardsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("no config directory: %w", err)
+	}
+	return filepath.Join(dir, "picopeeker", "boards.json"), nil
+}
+
+// The file keeps addresses in hex so it can be written by hand
+type regionFile struct {
+	Name     string `json:"name"`
+	Start    string `json:"start"`
+	End      string `json:"end"`
+	Access   string `json:"access"`
+	AliasOf  string `json:"alias_of,omitempty"`
+	NoSearch bool   `json:"no_search,omitempty"`
+}
+
+type boardFile struct {
+	Name    string       `json:"name"`
+	Chip    string       `json:"chip"`
+	Regions []regionFile `json:"regions"`
+}
+
+// LoadBoards reads the board definitions, or returns none if there is no boards file
+func LoadBoards() ([]Board, error) {
+	path, err := BoardsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read board definitions: %w", err)
+	}
+	boards, err := ParseBoards(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return boards, nil
+}
+
+// ParseBoards parses and checks board definitions in the boards file format
+func ParseBoards(data []byte) ([]Board, error) {
+	var file struct {
+		Boards []boardFile `json:"boards"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, err
+	}
+
+	var boards []Board
+	for _, bf := range file.Boards {
+		board, err := parseBoard(bf)
+		if err != nil {
+			return nil, fmt.Errorf("board %q: %w", bf.Name, err)
+		}
+		boards = append(boards, board)
+	}
+	return boards, nil
+}
+
+func parseBoard(bf boardFile) (Board, error) {
+	board := Board{Name: strings.TrimSpace(bf.Name), Chip: strings.ToUpper(bf.Chip)}
+	if board.Name == "" {
+		return board, fmt.Errorf("missing name")
+	}
+	if board.Chip != "RP2040" && board.Chip != "RP2350" {
+		return board, fmt.Errorf("chip must be RP2040 or RP2350, not %q", bf.Chip)
+	}
+
+	names := make(map[string]bool)
+	for _, rf := range bf.Regions {
+		region := Region{Name: strings.TrimSpace(rf.Name), AliasOf: rf.AliasOf, NoSearch: rf.NoSearch}
+		if region.Name == "" {
+			return board, fmt.Errorf("region without a name")
+		}
+		if names[region.Name] {
+			return board, fmt.Errorf("region %q is defined twice", region.Name)
+		}
+		names[region.Name] = true
+
+		start, err := strconv.ParseUint(strings.TrimSpace(rf.Start), 0, 32)
+		if err != nil {
+			return board, fmt.Errorf("region %q: invalid start %q", region.Name, rf.Start)
+		}
+		end, err := strconv.ParseUint(strings.TrimSpace(rf.End), 0, 32)
+		if err != nil {
+			return board, fmt.Errorf("region %q: invalid end %q", region.Name, rf.End)
+		}
+		if end <= start {
+			return board, fmt.Errorf("region %q: end must be after start", region.Name)
+		}
+		region.Start, region.End = uint32(start), uint32(end)
+
+		flags := rf.Access
+		if flags == "" {
+			flags = "r"
+		}
+		if region.Access, err = ParseAccess(flags); err != nil {
+			return board, fmt.Errorf("region %q: %w", region.Name, err)
+		}
+		board.Regions = append(board.Regions, region)
+	}
+
+	for _, region := range board.Regions {
+		if region.AliasOf != "" && !names[region.AliasOf] {
+			return board, fmt.Errorf("region %q is an alias of unknown region %q", region.Name, region.AliasOf)
+		}
+	}
+	for _, required := range []string{"Flash", "SRAM"} {
+		if !names[required] {
+			return board, fmt.Errorf("needs a region named %q", required)
+		}
+	}
+	return board, nil
+}
@@ -1,5 +1,10 @@
 package config
 
+import (
+	"fmt"
+	"slices"
+)
+
 // PicoModel represents the selected Pico model
 type PicoModel int
 
@@ -8,26 +13,49 @@ const (
 	Pico2
 )
 
+// Where the fixed regions are mapped on both RP2040 and RP2350
+const (
+	ROMStart    = 0x00000000
+	ROMEnd      = 0x00004000 // The firmware's readable ROM range (PICOPEEKER_ROM_END)
+	FlashStart  = 0x10000000
+	SRAMStart   = 0x20000000
+	PeriphStart = 0x40000000
+	PeriphEnd   = 0x60000000
+)
+
+// Aliases and external memory. Reads through the uncached (XIP_NOCACHE_NOALLOC)
+// views go straight to the flash or PSRAM chip instead of returning stale cache lines.
+const (
+	RP2040FlashNoCache  = 0x13000000
+	RP2040SRAMBanked    = 0x21000000 // SRAM0-3 without striping
+	RP2040SRAMBankedEnd = 0x21040000
+	RP2350FlashNoCache  = 0x14000000
+	PSRAMStart          = 0x11000000 // RP2350 XIP chip select 1
+	PSRAMNoCache        = 0x15000000
+)
+
 // MemoryRegions holds the memory map for a Pico model
 type MemoryRegions struct {
-	SRAMSize     string // Human-readable size
-	FlashSize    string // Human-readable size
-	SRAMSizeHex  uint32 // Size in bytes
-	FlashSizeHex uint32 // Size in bytes
+	SRAMSize     string   // Human-readable size
+	FlashSize    string   // Human-readable size
+	SRAMSizeHex  uint32   // Size in bytes
+	FlashSizeHex uint32   // Size in bytes
+	Map          []Region // Every region, in the order they are offered in the UI
 }
 
 // GetMemoryRegions returns the memory configuration for the selected Pico model
 func GetMemoryRegions(model PicoModel) MemoryRegions {
+	var regions MemoryRegions
 	switch model {
 	case Pico1:
-		return MemoryRegions{
+		regions = MemoryRegions{
 			SRAMSize:     "264KB",
 			FlashSize:    "2MB",
 			SRAMSizeHex:  0x42000,  // 264KB
 			FlashSizeHex: 0x200000, // 2MB
 		}
 	case Pico2:
-		return MemoryRegions{
+		regions = MemoryRegions{
 			SRAMSize:     "520KB",
 			FlashSize:    "4MB",
 			SRAMSizeHex:  0x82000,  // 520KB
@@ -36,6 +64,154 @@ func GetMemoryRegions(model PicoModel) MemoryRegions {
 	default:
 		return GetMemoryRegions(Pico2) // Default to Pico 2
 	}
+	flashNoCache := uint32(RP2350FlashNoCache)
+	if model == Pico1 {
+		flashNoCache = RP2040FlashNoCache
+	}
+	regions.Map = []Region{
+		{Name: "SRAM", Start: SRAMStart, End: SRAMStart + regions.SRAMSizeHex, Access: AccessRead | AccessWrite | AccessExec},
+		{Name: "Flash", Start: FlashStart, End: FlashStart + regions.FlashSizeHex, Access: AccessRead | AccessExec},
+		{Name: "ROM", Start: ROMStart, End: ROMEnd, Access: AccessRead | AccessExec},
+		{Name: "Flash (uncached)", Start: flashNoCache, End: flashNoCache + regions.FlashSizeHex, Access: AccessRead, AliasOf: "Flash"},
+	}
+	if model == Pico1 {
+		regions.Map = append(regions.Map, Region{Name: "SRAM (banked)", Start: RP2040SRAMBanked, End: RP2040SRAMBankedEnd, Access: AccessRead | AccessWrite, AliasOf: "SRAM"})
+	}
+	regions.Map = append(regions.Map, Region{Name: "Peripherals", Start: PeriphStart, End: PeriphEnd, Access: AccessRead | AccessWrite, NoSearch: true})
+	return regions
+}
+
+// WithPSRAM returns the map with PSRAM of the given size on XIP chip select 1
+// and its uncached alias added. A size of 0 leaves the map unchanged. These
+// are only guesses from the size: WithReported replaces them with the bounds
+// the firmware reports.
+func (m MemoryRegions) WithPSRAM(size uint32) MemoryRegions {
+	if size == 0 {
+		return m
+	}
+	return m.WithRegions([]Region{
+		{Name: "PSRAM", Start: PSRAMStart, End: PSRAMStart + size, Access: AccessRead | AccessWrite | AccessExec},
+		{Name: "PSRAM (uncached)", Start: PSRAMNoCache, End: PSRAMNoCache + size, Access: AccessRead | AccessWrite, AliasOf: "PSRAM"},
+	})
+}
+
+// GetDetectedMemoryRegions returns the memory configuration using sizes reported
+// by the device, falling back to the model defaults for any size that is 0
+func GetDetectedMemoryRegions(model PicoModel, sramSize, flashSize uint32) MemoryRegions {
+	regions := GetMemoryRegions(model)
+	if sramSize != 0 {
+		regions.SRAMSizeHex = sramSize
+		regions.SRAMSize = FormatSize(sramSize)
+		regions.resize("SRAM", sramSize)
+	}
+	if flashSize != 0 {
+		regions.FlashSizeHex = flashSize
+		regions.FlashSize = FormatSize(flashSize)
+		regions.resize("Flash", flashSize)
+	}
+	return regions
+}
+
+// resize changes the size of a region in the map, and of aliases that mirror
+// all of it, copying the map first so other MemoryRegions sharing it are not
+// affected
+func (m *MemoryRegions) resize(name string, size uint32) {
+	region, ok := m.Find(name)
+	if !ok {
+		return
+	}
+	m.Map = slices.Clone(m.Map)
+	for i := range m.Map {
+		r := &m.Map[i]
+		if r.Name == name || r.AliasOf == name && r.Size() == region.Size() {
+			r.End = r.Start + size
+		}
+	}
+}
+
+// WithRegions returns the map with extra regions added. Regions with a name
+// already in the map are skipped, so use WithReported for the firmware's table.
+func (m MemoryRegions) WithRegions(extra []Region) MemoryRegions {
+	m.Map = slices.Clone(m.Map)
+	for _, region := range extra {
+		if _, ok := m.Find(region.Name); !ok {
+			m.Map = append(m.Map, region)
+		}
+	}
+	return m
+}
+
+// WithReported returns the map made of the regions the firmware reported, which
+// are the ranges its READ and SEARCH commands accept. Regions the map knows
+// keep their access and flags but take the reported bounds; regions the
+// firmware didn't report are dropped, and ones the map doesn't know are added.
+func (m MemoryRegions) WithReported(reported []Region) MemoryRegions {
+	var regions []Region
+	for _, region := range m.Map {
+		if i := slices.IndexFunc(reported, func(r Region) bool { return r.Name == region.Name }); i != -1 {
+			region.Start, region.End = reported[i].Start, reported[i].End
+			regions = append(regions, region)
+		}
+	}
+	for _, region := range reported {
+		if _, ok := m.Find(region.Name); !ok {
+			regions = append(regions, region)
+		}
+	}
+	m.Map = regions
+
+	if sram, ok := m.Find("SRAM"); ok {
+		m.SRAMSizeHex, m.SRAMSize = sram.Size(), FormatSize(sram.Size())
+	}
+	if flash, ok := m.Find("Flash"); ok {
+		m.FlashSizeHex, m.FlashSize = flash.Size(), FormatSize(flash.Size())
+	}
+	return m
+}
+
+// Find returns the region with the given name
+func (m MemoryRegions) Find(name string) (Region, bool) {
+	for _, region := range m.Map {
+		if region.Name == name {
+			return region, true
+		}
+	}
+	return Region{}, false
+}
+
+// RegionAt returns the region containing addr. Regions that aren't aliases win.
+func (m MemoryRegions) RegionAt(addr uint32) (Region, bool) {
+	var found Region
+	ok := false
+	for _, region := range m.Map {
+		if region.Contains(addr) && (!ok || found.AliasOf != "" && region.AliasOf == "") {
+			found, ok = region, true
+		}
+	}
+	return found, ok
+}
+
+// Searchable returns the regions offered for quick access, searches and scans
+func (m MemoryRegions) Searchable() []Region {
+	var regions []Region
+	for _, region := range m.Map {
+		if !region.NoSearch && region.Access&AccessRead != 0 {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// FormatSize returns a human-readable size such as "264KB" or "4MB"
+func FormatSize(size uint32) string {
+	switch {
+	case size >= 1024*1024 && size%(1024*1024) == 0:
+		return fmt.Sprintf("%dMB", size/(1024*1024))
+	case size >= 1024 && size%1024 == 0:
+		return fmt.Sprintf("%dKB", size/1024)
+	default:
+		return fmt.Sprintf("%d bytes", size)
+	}
 }
 
 // GetModelFromString converts a string to PicoModel
@@ -61,3 +237,51 @@ func GetModelString(model PicoModel) string {
 		return "Pico 2 (RP2350)"
 	}
 }
+
+// GetModelFromChip converts a chip name reported by the firmware to PicoModel
+func GetModelFromChip(chip string) PicoModel {
+	switch chip {
+	case "RP2040":
+		return Pico1
+	default:
+		return Pico2
+	}
+}
+
+// CoreArch is the instruction set the Pico's cores are running
+type CoreArch int
+
+const (
+	ArchARM   CoreArch = iota // Cortex-M0+ (RP2040) or Cortex-M33 (RP2350)
+	ArchRISCV                 // Hazard3 RV32IMAC (RP2350 only)
+)
+
+// GetArchString returns the display string for a core architecture
+func GetArchString(arch CoreArch) string {
+	switch arch {
+	case ArchRISCV:
+		return "RISC-V (Hazard3)"
+	default:
+		return "ARM (Cortex-M)"
+	}
+}
+
+// GetArchFromString converts a display string to CoreArch
+func GetArchFromString(arch string) CoreArch {
+	switch arch {
+	case "RISC-V (Hazard3)":
+		return ArchRISCV
+	default:
+		return ArchARM
+	}
+}
+
+// GetArchFromInfo converts the arch reported by the firmware to CoreArch
+func GetArchFromInfo(arch string) CoreArch {
+	switch arch {
+	case "riscv":
+		return ArchRISCV
+	default:
+		return ArchARM
+	}
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestWithReported(t *testing.T) {
+	// JEDEC says 16MB, but the firmware was built to read only the first 4MB
+	regions := GetDetectedMemoryRegions(Pico2, 0x82000, 16*1024*1024)
+	reported := []Region{
+		{Name: "ROM", Start: 0x00000000, End: 0x00004000, Access: AccessRead},
+		{Name: "Flash", Start: 0x10000000, End: 0x10400000, Access: AccessRead},
+		{Name: "SRAM", Start: 0x20000000, End: 0x20082000, Access: AccessRead},
+		{Name: "Flash (uncached)", Start: 0x14000000, End: 0x14400000, Access: AccessRead},
+		{Name: "Custom", Start: 0x50100000, End: 0x50101000, Access: AccessRead},
+		{Name: "Peripherals", Start: 0x40000000, End: 0x60000000, Access: AccessRead},
+	}
+	regions = regions.WithReported(reported)
+
+	want := []Region{
+		{Name: "SRAM", Start: 0x20000000, End: 0x20082000, Access: AccessRead | AccessWrite | AccessExec},
+		{Name: "Flash", Start: 0x10000000, End: 0x10400000, Access: AccessRead | AccessExec},
+		{Name: "ROM", Start: 0x00000000, End: 0x00004000, Access: AccessRead | AccessExec},
+		{Name: "Flash (uncached)", Start: 0x14000000, End: 0x14400000, Access: AccessRead, AliasOf: "Flash"},
+		{Name: "Peripherals", Start: PeriphStart, End: PeriphEnd, Access: AccessRead | AccessWrite, NoSearch: true},
+		{Name: "Custom", Start: 0x50100000, End: 0x50101000, Access: AccessRead},
+	}
+	if !slices.Equal(regions.Map, want) {
+		t.Errorf("WithReported map =\n%+v\nwant\n%+v", regions.Map, want)
+	}
+	if regions.FlashSizeHex != 0x400000 || regions.FlashSize != "4MB" {
+		t.Errorf("flash size = 0x%x (%s), want the reported 4MB", regions.FlashSizeHex, regions.FlashSize)
+	}
+}
+
+func TestWithReportedDropsUnreported(t *testing.T) {
+	// Firmware from before the aliases were added reports no uncached or banked views
+	regions := GetMemoryRegions(Pico1).WithReported([]Region{
+		{Name: "SRAM", Start: 0x20000000, End: 0x20042000, Access: AccessRead},
+		{Name: "Flash", Start: 0x10000000, End: 0x10200000, Access: AccessRead},
+	})
+	for _, name := range []string{"Flash (uncached)", "SRAM (banked)", "Peripherals"} {
+		if _, ok := regions.Find(name); ok {
+			t.Errorf("%s is offered although the firmware doesn't read it", name)
+		}
+	}
+}
+
+func TestWithReportedOverridesPSRAM(t *testing.T) {
+	// The size says 8MB, but the firmware maps only 2MB of it
+	regions := GetMemoryRegions(Pico2).WithPSRAM(8 * 1024 * 1024)
+	regions = regions.WithReported(append(GetMemoryRegions(Pico2).Map,
+		Region{Name: "PSRAM", Start: PSRAMStart, End: PSRAMStart + 0x200000, Access: AccessRead},
+		Region{Name: "PSRAM (uncached)", Start: PSRAMNoCache, End: PSRAMNoCache + 0x200000, Access: AccessRead},
+	))
+
+	psram, ok := regions.Find("PSRAM")
+	if !ok || psram.End != PSRAMStart+0x200000 || psram.Access&AccessWrite == 0 {
+		t.Errorf("PSRAM = %+v, want the reported 2MB with its read/write access kept", psram)
+	}
+	alias, ok := regions.Find("PSRAM (uncached)")
+	if !ok || alias.End != PSRAMNoCache+0x200000 || alias.AliasOf != "PSRAM" {
+		t.Errorf("PSRAM (uncached) = %+v, want the reported 2MB, still an alias of PSRAM", alias)
+	}
+	if region, _ := regions.RegionAt(PSRAMStart + 0x300000); region.Name != "" {
+		t.Errorf("0x%08x is in %s, but the firmware doesn't map it", PSRAMStart+0x300000, region.Name)
+	}
+}
@@ -0,0 +1,91 @@
+package disasm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Instruction is one decoded instruction
+type Instruction struct {
+	Address   uint32
+	Size      int    // Bytes: 2 or 4
+	Raw       uint32 // Encoding, first halfword in the upper 16 bits for 32-bit Thumb
+	Mnemonic  string
+	Operands  string
+	Target    uint32 // Branch target or literal address
+	HasTarget bool
+	IsBranch  bool // Target is code (branch/call) rather than a literal
+	Halfwords bool // 32-bit encodings are listed as two halfwords (Thumb)
+}
+
+// Text returns the instruction in assembler syntax, e.g. "ldr r0, [pc, #32]"
+func (i Instruction) Text() string {
+	if i.Operands == "" {
+		return i.Mnemonic
+	}
+	return fmt.Sprintf("%-7s %s", i.Mnemonic, i.Operands)
+}
+
+// Encoding returns the raw bytes in the usual listing form ("4808" or "f000 f8a3")
+func (i Instruction) Encoding() string {
+	switch i.Size {
+	case 2:
+		return fmt.Sprintf("%04x", i.Raw)
+	case 4:
+		if i.Halfwords {
+			return fmt.Sprintf("%04x %04x", i.Raw>>16, i.Raw&0xFFFF)
+		}
+		return fmt.Sprintf("%08x", i.Raw)
+	default:
+		return fmt.Sprintf("%x", i.Raw)
+	}
+}
+
+// Symbolizer names an address (e.g. "main" or "main+0x10"), returning "" if unknown
+type Symbolizer func(addr uint32) string
+
+// FormatListing renders instructions as an objdump-style listing, with labels
+// for symbols and branch/literal targets annotated with their symbol names
+func FormatListing(title string, insts []Instruction, symbolize Symbolizer) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("=== %s ===\n\n", title))
+
+	for _, inst := range insts {
+		if symbolize != nil {
+			// Print a label where a symbol starts
+			if name := symbolize(inst.Address); name != "" && !strings.Contains(name, "+") {
+				sb.WriteString(fmt.Sprintf("\n%08x <%s>:\n", inst.Address, name))
+			}
+		}
+		sb.WriteString(FormatLine(inst))
+		if inst.HasTarget {
+			sb.WriteString(FormatTarget(inst, symbolize))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n===END===\n")
+	return sb.String()
+}
+
+// FormatLine returns the listing line for an instruction, without the target annotation
+func FormatLine(inst Instruction) string {
+	return fmt.Sprintf("%08x:  %-10s  %s", inst.Address, inst.Encoding(), inst.Text())
+}
+
+// FormatTarget returns the annotation for an instruction's target, e.g. "  ; 0x10000380 <main>"
+func FormatTarget(inst Instruction, symbolize Symbolizer) string {
+	if !inst.HasTarget {
+		return ""
+	}
+	if symbolize != nil {
+		if name := symbolize(inst.Target); name != "" {
+			return fmt.Sprintf("  ; 0x%08x <%s>", inst.Target, name)
+		}
+	}
+	if inst.IsBranch {
+		// The operand already shows the address
+		return ""
+	}
+	return fmt.Sprintf("  ; 0x%08x", inst.Target)
+}
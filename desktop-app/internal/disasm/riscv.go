@@ -0,0 +1,591 @@
+package disasm
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Decoder for the RP2350's Hazard3 cores: RV32IMAC with Zicsr, Zifencei, Zba,
+// Zbb, Zbc, Zbs, Zbkb, Zcb and Zcmp. Common pseudo-instructions (li, mv, ret,
+// j, ...) are shown the way objdump does; compressed instructions are shown
+// expanded, except the Zcmp push/pop and move instructions.
+
+var rvRegs = [32]string{"zero", "ra", "sp", "gp", "tp", "t0", "t1", "t2",
+	"s0", "s1", "a0", "a1", "a2", "a3", "a4", "a5",
+	"a6", "a7", "s2", "s3", "s4", "s5", "s6", "s7",
+	"s8", "s9", "s10", "s11", "t3", "t4", "t5", "t6"}
+
+var rvCSRs = map[uint32]string{
+	0x300: "mstatus", 0x301: "misa", 0x304: "mie", 0x305: "mtvec", 0x310: "mstatush",
+	0x340: "mscratch", 0x341: "mepc", 0x342: "mcause", 0x343: "mtval", 0x344: "mip",
+	0xB00: "mcycle", 0xB02: "minstret", 0xB80: "mcycleh", 0xB82: "minstreth",
+	0xC00: "cycle", 0xC01: "time", 0xC02: "instret", 0xC80: "cycleh", 0xC81: "timeh", 0xC82: "instreth",
+	0xF11: "mvendorid", 0xF12: "marchid", 0xF13: "mimpid", 0xF14: "mhartid",
+	// Hazard3 interrupt controller
+	0xBE0: "meiea", 0xBE1: "meipa", 0xBE2: "meifa", 0xBE3: "meipra", 0xBE4: "meinext", 0xBE5: "meicontext",
+}
+
+// DecodeRISCV decodes RV32 code. data is little-endian as read from memory and
+// addr is the address of data[0] (must be halfword aligned).
+func DecodeRISCV(data []byte, addr uint32) []Instruction {
+	var insts []Instruction
+
+	// auipc results per register, so the following addi/load/jalr gets a target
+	var auipcReg uint32
+	var auipcValue uint32
+	auipcValid := false
+
+	for i := 0; i+1 < len(data); {
+		pc := addr + uint32(i)
+		half := binary.LittleEndian.Uint16(data[i:])
+
+		var inst Instruction
+		if half&3 != 3 {
+			inst = decodeRVCompressed(uint32(half), pc)
+			inst.Size = 2
+			inst.Raw = uint32(half)
+		} else {
+			if i+3 >= len(data) {
+				insts = append(insts, Instruction{Address: pc, Size: 2, Raw: uint32(half), Mnemonic: ".2byte", Operands: fmt.Sprintf("0x%04x", half)})
+				break
+			}
+			word := binary.LittleEndian.Uint32(data[i:])
+			inst = decodeRV32(word, pc)
+			inst.Size = 4
+			inst.Raw = word
+		}
+		inst.Address = pc
+
+		// Resolve auipc pairs: auipc a0, 0x1 / addi a0, a0, -16
+		raw := inst.Raw
+		if inst.Size == 4 && auipcValid && !inst.HasTarget {
+			opcode := raw & 0x7F
+			rs1 := (raw >> 15) & 0x1F
+			offset := uint32(int32(raw) >> 20)
+			if rs1 == auipcReg && (opcode == 0x13 && (raw>>12)&7 == 0 || opcode == 0x03 || opcode == 0x67) {
+				inst.Target = auipcValue + offset
+				inst.HasTarget = true
+				inst.IsBranch = opcode == 0x67
+			}
+		}
+		auipcValid = inst.Size == 4 && raw&0x7F == 0x17
+		if auipcValid {
+			auipcReg = (raw >> 7) & 0x1F
+			auipcValue = pc + raw&0xFFFFF000
+		}
+
+		insts = append(insts, inst)
+		i += inst.Size
+	}
+
+	return insts
+}
+
+func csrName(csr uint32) string {
+	if name, ok := rvCSRs[csr]; ok {
+		return name
+	}
+	return fmt.Sprintf("0x%03x", csr)
+}
+
+func decodeRV32(w uint32, pc uint32) Instruction {
+	opcode := w & 0x7F
+	rd := (w >> 7) & 0x1F
+	funct3 := (w >> 12) & 7
+	rs1 := (w >> 15) & 0x1F
+	rs2 := (w >> 20) & 0x1F
+	funct7 := w >> 25
+	immI := int32(w) >> 20
+	immS := int32(w)>>25<<5 | int32((w>>7)&0x1F)
+
+	switch opcode {
+	case 0x37: // LUI
+		return op("lui", "%s, 0x%x", rvRegs[rd], w>>12)
+	case 0x17: // AUIPC
+		return op("auipc", "%s, 0x%x", rvRegs[rd], w>>12)
+
+	case 0x6F: // JAL
+		offset := (w>>31)<<20 | ((w>>12)&0xFF)<<12 | ((w>>20)&1)<<11 | ((w>>21)&0x3FF)<<1
+		target := uint32(int32(pc) + signExtend(offset, 21))
+		switch rd {
+		case 0:
+			return branch("j", target)
+		case 1:
+			return branch("jal", target)
+		}
+		inst := branch("jal", target)
+		inst.Operands = fmt.Sprintf("%s, 0x%08x", rvRegs[rd], target)
+		return inst
+
+	case 0x67: // JALR
+		if funct3 != 0 {
+			break
+		}
+		switch {
+		case rd == 0 && rs1 == 1 && immI == 0:
+			return op("ret", "")
+		case rd == 0 && immI == 0:
+			return op("jr", "%s", rvRegs[rs1])
+		case rd == 1 && immI == 0:
+			return op("jalr", "%s", rvRegs[rs1])
+		}
+		return op("jalr", "%s, %d(%s)", rvRegs[rd], immI, rvRegs[rs1])
+
+	case 0x63: // Branches
+		offset := (w>>31)<<12 | ((w>>7)&1)<<11 | ((w>>25)&0x3F)<<5 | ((w>>8)&0xF)<<1
+		target := uint32(int32(pc) + signExtend(offset, 13))
+		mnemonics := map[uint32]string{0: "beq", 1: "bne", 4: "blt", 5: "bge", 6: "bltu", 7: "bgeu"}
+		name, ok := mnemonics[funct3]
+		if !ok {
+			break
+		}
+		inst := branch(name, target)
+		if rs2 == 0 && (funct3 == 0 || funct3 == 1) {
+			inst.Mnemonic += "z"
+			inst.Operands = fmt.Sprintf("%s, 0x%08x", rvRegs[rs1], target)
+		} else {
+			inst.Operands = fmt.Sprintf("%s, %s, 0x%08x", rvRegs[rs1], rvRegs[rs2], target)
+		}
+		return inst
+
+	case 0x03: // Loads
+		mnemonics := map[uint32]string{0: "lb", 1: "lh", 2: "lw", 4: "lbu", 5: "lhu"}
+		if name, ok := mnemonics[funct3]; ok {
+			return op(name, "%s, %d(%s)", rvRegs[rd], immI, rvRegs[rs1])
+		}
+
+	case 0x23: // Stores
+		mnemonics := map[uint32]string{0: "sb", 1: "sh", 2: "sw"}
+		if name, ok := mnemonics[funct3]; ok {
+			return op(name, "%s, %d(%s)", rvRegs[rs2], immS, rvRegs[rs1])
+		}
+
+	case 0x13: // Immediate arithmetic
+		return decodeRVOpImm(w, rd, funct3, rs1, immI)
+
+	case 0x33: // Register arithmetic
+		return decodeRVOp(rd, funct3, rs1, rs2, funct7)
+
+	case 0x0F: // FENCE, FENCE.I
+		if funct3 == 1 {
+			return op("fence.i", "")
+		}
+		if w == 0x0FF0000F {
+			return op("fence", "")
+		}
+		return op("fence", "%s, %s", fenceSet((w>>24)&0xF), fenceSet((w>>20)&0xF))
+
+	case 0x73: // SYSTEM
+		return decodeRVSystem(w, rd, funct3, rs1)
+
+	case 0x2F: // Atomics
+		if funct3 != 2 {
+			break
+		}
+		funct5 := w >> 27
+		names := map[uint32]string{0x02: "lr.w", 0x03: "sc.w", 0x01: "amoswap.w", 0x00: "amoadd.w",
+			0x04: "amoxor.w", 0x0C: "amoand.w", 0x08: "amoor.w", 0x10: "amomin.w", 0x14: "amomax.w",
+			0x18: "amominu.w", 0x1C: "amomaxu.w"}
+		name, ok := names[funct5]
+		if !ok {
+			break
+		}
+		switch (w >> 25) & 3 {
+		case 1:
+			name += ".rl"
+		case 2:
+			name += ".aq"
+		case 3:
+			name += ".aqrl"
+		}
+		if funct5 == 0x02 {
+			return op(name, "%s, (%s)", rvRegs[rd], rvRegs[rs1])
+		}
+		return op(name, "%s, %s, (%s)", rvRegs[rd], rvRegs[rs2], rvRegs[rs1])
+	}
+
+	return op(".4byte", "0x%08x", w)
+}
+
+func fenceSet(bits uint32) string {
+	set := ""
+	for i, c := range "iorw" {
+		if bits&(8>>i) != 0 {
+			set += string(c)
+		}
+	}
+	return set
+}
+
+func decodeRVOpImm(w, rd, funct3, rs1 uint32, imm int32) Instruction {
+	shamt := (w >> 20) & 0x1F
+	funct7 := w >> 25
+
+	switch funct3 {
+	case 0:
+		switch {
+		case rd == 0 && rs1 == 0 && imm == 0:
+			return op("nop", "")
+		case rs1 == 0:
+			return op("li", "%s, %d", rvRegs[rd], imm)
+		case imm == 0:
+			return op("mv", "%s, %s", rvRegs[rd], rvRegs[rs1])
+		}
+		return op("addi", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], imm)
+	case 2:
+		return op("slti", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], imm)
+	case 3:
+		if imm == 1 {
+			return op("seqz", "%s, %s", rvRegs[rd], rvRegs[rs1])
+		}
+		return op("sltiu", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], imm)
+	case 4:
+		if imm == -1 {
+			return op("not", "%s, %s", rvRegs[rd], rvRegs[rs1])
+		}
+		return op("xori", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], imm)
+	case 6:
+		return op("ori", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], imm)
+	case 7:
+		return op("andi", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], imm)
+
+	case 1:
+		switch funct7 {
+		case 0x00:
+			return op("slli", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], shamt)
+		case 0x14:
+			return op("bseti", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], shamt)
+		case 0x24:
+			return op("bclri", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], shamt)
+		case 0x04:
+			if shamt == 0x0F {
+				return op("zip", "%s, %s", rvRegs[rd], rvRegs[rs1])
+			}
+		case 0x34:
+			return op("binvi", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], shamt)
+		case 0x30:
+			// Zbb unary operations, selected by the rs2 field
+			names := map[uint32]string{0: "clz", 1: "ctz", 2: "cpop", 4: "sext.b", 5: "sext.h"}
+			if name, ok := names[shamt]; ok {
+				return op(name, "%s, %s", rvRegs[rd], rvRegs[rs1])
+			}
+		}
+	case 5:
+		switch {
+		case funct7 == 0x00:
+			return op("srli", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], shamt)
+		case funct7 == 0x20:
+			return op("srai", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], shamt)
+		case funct7 == 0x30:
+			return op("rori", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], shamt)
+		case funct7 == 0x24:
+			return op("bexti", "%s, %s, %d", rvRegs[rd], rvRegs[rs1], shamt)
+		case w>>20 == 0x287:
+			return op("orc.b", "%s, %s", rvRegs[rd], rvRegs[rs1])
+		case w>>20 == 0x698:
+			return op("rev8", "%s, %s", rvRegs[rd], rvRegs[rs1])
+		case w>>20 == 0x687:
+			return op("brev8", "%s, %s", rvRegs[rd], rvRegs[rs1])
+		case w>>20 == 0x08F:
+			return op("unzip", "%s, %s", rvRegs[rd], rvRegs[rs1])
+		}
+	}
+
+	return op(".4byte", "0x%08x", w)
+}
+
+func decodeRVOp(rd, funct3, rs1, rs2, funct7 uint32) Instruction {
+	type key struct{ funct7, funct3 uint32 }
+	names := map[key]string{
+		{0x00, 0}: "add", {0x20, 0}: "sub", {0x00, 1}: "sll", {0x00, 2}: "slt", {0x00, 3}: "sltu",
+		{0x00, 4}: "xor", {0x00, 5}: "srl", {0x20, 5}: "sra", {0x00, 6}: "or", {0x00, 7}: "and",
+		// M
+		{0x01, 0}: "mul", {0x01, 1}: "mulh", {0x01, 2}: "mulhsu", {0x01, 3}: "mulhu",
+		{0x01, 4}: "div", {0x01, 5}: "divu", {0x01, 6}: "rem", {0x01, 7}: "remu",
+		// Zba
+		{0x10, 2}: "sh1add", {0x10, 4}: "sh2add", {0x10, 6}: "sh3add",
+		// Zbb
+		{0x20, 7}: "andn", {0x20, 6}: "orn", {0x20, 4}: "xnor",
+		{0x05, 4}: "min", {0x05, 5}: "minu", {0x05, 6}: "max", {0x05, 7}: "maxu",
+		{0x30, 1}: "rol", {0x30, 5}: "ror",
+		// Zbc
+		{0x05, 1}: "clmul", {0x05, 3}: "clmulh", {0x05, 2}: "clmulr",
+		// Zbs
+		{0x24, 1}: "bclr", {0x24, 5}: "bext", {0x34, 1}: "binv", {0x14, 1}: "bset",
+		// Zbkb
+		{0x04, 4}: "pack", {0x04, 7}: "packh",
+	}
+
+	// zext.h is pack with rs2 = zero
+	if funct7 == 0x04 && funct3 == 4 && rs2 == 0 {
+		return op("zext.h", "%s, %s", rvRegs[rd], rvRegs[rs1])
+	}
+
+	name, ok := names[key{funct7, funct3}]
+	if !ok {
+		return op(".4byte", "0x%08x", funct7<<25|rs2<<20|rs1<<15|funct3<<12|rd<<7|0x33)
+	}
+	switch {
+	case name == "sub" && rs1 == 0:
+		return op("neg", "%s, %s", rvRegs[rd], rvRegs[rs2])
+	case name == "sltu" && rs1 == 0:
+		return op("snez", "%s, %s", rvRegs[rd], rvRegs[rs2])
+	}
+	return op(name, "%s, %s, %s", rvRegs[rd], rvRegs[rs1], rvRegs[rs2])
+}
+
+func decodeRVSystem(w, rd, funct3, rs1 uint32) Instruction {
+	csr := w >> 20
+
+	switch w {
+	case 0x00000073:
+		return op("ecall", "")
+	case 0x00100073:
+		return op("ebreak", "")
+	case 0x30200073:
+		return op("mret", "")
+	case 0x10500073:
+		return op("wfi", "")
+	}
+
+	name := csrName(csr)
+	switch funct3 {
+	case 1:
+		if rd == 0 {
+			return op("csrw", "%s, %s", name, rvRegs[rs1])
+		}
+		return op("csrrw", "%s, %s, %s", rvRegs[rd], name, rvRegs[rs1])
+	case 2:
+		if rs1 == 0 {
+			return op("csrr", "%s, %s", rvRegs[rd], name)
+		}
+		if rd == 0 {
+			return op("csrs", "%s, %s", name, rvRegs[rs1])
+		}
+		return op("csrrs", "%s, %s, %s", rvRegs[rd], name, rvRegs[rs1])
+	case 3:
+		if rd == 0 {
+			return op("csrc", "%s, %s", name, rvRegs[rs1])
+		}
+		return op("csrrc", "%s, %s, %s", rvRegs[rd], name, rvRegs[rs1])
+	case 5:
+		if rd == 0 {
+			return op("csrwi", "%s, %d", name, rs1)
+		}
+		return op("csrrwi", "%s, %s, %d", rvRegs[rd], name, rs1)
+	case 6:
+		if rd == 0 {
+			return op("csrsi", "%s, %d", name, rs1)
+		}
+		return op("csrrsi", "%s, %s, %d", rvRegs[rd], name, rs1)
+	case 7:
+		if rd == 0 {
+			return op("csrci", "%s, %d", name, rs1)
+		}
+		return op("csrrci", "%s, %s, %d", rvRegs[rd], name, rs1)
+	}
+
+	return op(".4byte", "0x%08x", w)
+}
+
+// decodeRVCompressed decodes a 16-bit RVC (and Zcb) instruction
+func decodeRVCompressed(h uint32, pc uint32) Instruction {
+	funct3 := h >> 13
+	// Registers x8-x15 in the 3-bit fields
+	rdP := rvRegs[8+(h>>2)&7]
+	rs1P := rvRegs[8+(h>>7)&7]
+	rd := (h >> 7) & 0x1F
+	rs2 := (h >> 2) & 0x1F
+
+	switch h & 3 {
+	case 0:
+		switch funct3 {
+		case 0:
+			imm := ((h>>7)&0xF)<<6 | ((h>>11)&3)<<4 | ((h>>5)&1)<<3 | ((h>>6)&1)<<2
+			if imm == 0 {
+				break // Illegal instruction
+			}
+			return op("addi", "%s, sp, %d", rdP, imm)
+		case 2:
+			offset := ((h>>5)&1)<<6 | ((h>>10)&7)<<3 | ((h>>6)&1)<<2
+			return op("lw", "%s, %d(%s)", rdP, offset, rs1P)
+		case 6:
+			offset := ((h>>5)&1)<<6 | ((h>>10)&7)<<3 | ((h>>6)&1)<<2
+			return op("sw", "%s, %d(%s)", rdP, offset, rs1P)
+		case 4:
+			// Zcb byte and halfword loads/stores
+			uimm := (h>>5)&1<<1 | (h>>6)&1
+			switch (h >> 10) & 7 {
+			case 0:
+				return op("lbu", "%s, %d(%s)", rdP, uimm, rs1P)
+			case 1:
+				if h&0x40 != 0 {
+					return op("lh", "%s, %d(%s)", rdP, uimm&2, rs1P)
+				}
+				return op("lhu", "%s, %d(%s)", rdP, uimm&2, rs1P)
+			case 2:
+				return op("sb", "%s, %d(%s)", rdP, uimm, rs1P)
+			case 3:
+				if h&0x40 == 0 {
+					return op("sh", "%s, %d(%s)", rdP, uimm&2, rs1P)
+				}
+			}
+		}
+
+	case 1:
+		imm6 := signExtend(((h>>12)&1)<<5|(h>>2)&0x1F, 6)
+		switch funct3 {
+		case 0:
+			if rd == 0 {
+				return op("nop", "")
+			}
+			return op("addi", "%s, %s, %d", rvRegs[rd], rvRegs[rd], imm6)
+		case 1, 5:
+			offset := ((h>>12)&1)<<11 | ((h>>8)&1)<<10 | ((h>>9)&3)<<8 | ((h>>6)&1)<<7 |
+				((h>>7)&1)<<6 | ((h>>2)&1)<<5 | ((h>>11)&1)<<4 | ((h>>3)&7)<<1
+			target := uint32(int32(pc) + signExtend(offset, 12))
+			if funct3 == 1 {
+				return branch("jal", target)
+			}
+			return branch("j", target)
+		case 2:
+			return op("li", "%s, %d", rvRegs[rd], imm6)
+		case 3:
+			if rd == 2 {
+				imm := ((h>>12)&1)<<9 | ((h>>3)&3)<<7 | ((h>>5)&1)<<6 | ((h>>2)&1)<<5 | ((h>>6)&1)<<4
+				return op("addi", "sp, sp, %d", signExtend(imm, 10))
+			}
+			return op("lui", "%s, 0x%x", rvRegs[rd], uint32(imm6)&0xFFFFF)
+		case 4:
+			return decodeRVCompressedArith(h, rs1P, rdP, imm6)
+		case 6, 7:
+			offset := ((h>>12)&1)<<8 | ((h>>5)&3)<<6 | ((h>>2)&1)<<5 | ((h>>10)&3)<<3 | ((h>>3)&3)<<1
+			target := uint32(int32(pc) + signExtend(offset, 9))
+			name := "beqz"
+			if funct3 == 7 {
+				name = "bnez"
+			}
+			inst := branch(name, target)
+			inst.Operands = fmt.Sprintf("%s, 0x%08x", rs1P, target)
+			return inst
+		}
+
+	case 2:
+		switch funct3 {
+		case 0:
+			return op("slli", "%s, %s, %d", rvRegs[rd], rvRegs[rd], ((h>>12)&1)<<5|rs2)
+		case 2:
+			offset := ((h>>2)&3)<<6 | ((h>>12)&1)<<5 | ((h>>4)&7)<<2
+			return op("lw", "%s, %d(sp)", rvRegs[rd], offset)
+		case 4:
+			bit12 := (h >> 12) & 1
+			switch {
+			case bit12 == 0 && rs2 == 0 && rd != 0:
+				if rd == 1 {
+					return op("ret", "")
+				}
+				return op("jr", "%s", rvRegs[rd])
+			case bit12 == 0 && rd != 0:
+				return op("mv", "%s, %s", rvRegs[rd], rvRegs[rs2])
+			case bit12 == 1 && rd == 0 && rs2 == 0:
+				return op("ebreak", "")
+			case bit12 == 1 && rs2 == 0:
+				return op("jalr", "%s", rvRegs[rd])
+			case bit12 == 1:
+				return op("add", "%s, %s, %s", rvRegs[rd], rvRegs[rd], rvRegs[rs2])
+			}
+		case 5:
+			return decodeRVZcmp(h)
+		case 6:
+			offset := ((h>>7)&3)<<6 | ((h>>9)&0xF)<<2
+			return op("sw", "%s, %d(sp)", rvRegs[rs2], offset)
+		}
+	}
+
+	return op(".2byte", "0x%04x", h)
+}
+
+// decodeRVZcmp decodes the Zcmp push/pop and register pair moves, which use
+// the c.fsdsp encodings (Hazard3 has no floating point)
+func decodeRVZcmp(h uint32) Instruction {
+	// s0-s7 in the 3-bit fields of cm.mva01s and cm.mvsa01
+	sregs := [8]string{"s0", "s1", "s2", "s3", "s4", "s5", "s6", "s7"}
+
+	rlist := (h >> 4) & 0xF
+	if (h>>12)&1 == 1 && rlist >= 4 {
+		// ra and the first rlist-4 saved registers, except that 15 adds both s10 and s11
+		saved := int(rlist) - 3
+		regs := "{ra"
+		switch {
+		case rlist == 5:
+			regs += ", s0"
+		case rlist == 15:
+			saved = 13
+			regs += ", s0-s11"
+		case rlist > 5:
+			regs += fmt.Sprintf(", s0-s%d", rlist-5)
+		}
+		regs += "}"
+		// The registers take a 16-byte aligned frame, which spimm extends
+		adjust := (saved*4+15)/16*16 + int((h>>2)&3)*16
+
+		switch (h >> 8) & 0x1F {
+		case 0x18:
+			return op("cm.push", "%s, %d", regs, -adjust)
+		case 0x1A:
+			return op("cm.pop", "%s, %d", regs, adjust)
+		case 0x1C:
+			return op("cm.popretz", "%s, %d", regs, adjust)
+		case 0x1E:
+			return op("cm.popret", "%s, %d", regs, adjust)
+		}
+	}
+
+	if (h>>10)&7 == 3 {
+		r1s, r2s := sregs[(h>>7)&7], sregs[(h>>2)&7]
+		switch (h >> 5) & 3 {
+		case 1:
+			if r1s != r2s {
+				return op("cm.mvsa01", "%s, %s", r1s, r2s)
+			}
+		case 3:
+			return op("cm.mva01s", "%s, %s", r1s, r2s)
+		}
+	}
+
+	return op(".2byte", "0x%04x", h)
+}
+
+func decodeRVCompressedArith(h uint32, rs1P, rs2P string, imm6 int32) Instruction {
+	shamt := ((h>>12)&1)<<5 | (h>>2)&0x1F
+
+	switch (h >> 10) & 3 {
+	case 0:
+		return op("srli", "%s, %s, %d", rs1P, rs1P, shamt)
+	case 1:
+		return op("srai", "%s, %s, %d", rs1P, rs1P, shamt)
+	case 2:
+		return op("andi", "%s, %s, %d", rs1P, rs1P, imm6)
+	}
+
+	if h&0x1000 == 0 {
+		names := [4]string{"sub", "xor", "or", "and"}
+		return op(names[(h>>5)&3], "%s, %s, %s", rs1P, rs1P, rs2P)
+	}
+
+	// Zcb: c.mul and the unary operations
+	switch (h >> 5) & 3 {
+	case 2:
+		return op("mul", "%s, %s, %s", rs1P, rs1P, rs2P)
+	case 3:
+		names := map[uint32]string{0: "zext.b", 1: "sext.b", 2: "zext.h", 3: "sext.h", 5: "not"}
+		if name, ok := names[(h>>2)&7]; ok {
+			if name == "zext.b" {
+				return op("andi", "%s, %s, 255", rs1P, rs1P)
+			}
+			return op(name, "%s, %s", rs1P, rs1P)
+		}
+	}
+
+	return op(".2byte", "0x%04x", h)
+}
@@ -0,0 +1,52 @@
+package disasm
+
+import (
+	"encoding/binary"
+	"testing"
+)
+
+func TestDecodeRISCV(t *testing.T) {
+	tests := []struct {
+		raw  uint32
+		want string
+	}{
+		// Zbc
+		{0x0ab51533, "clmul a0, a0, a1"},
+		{0x0ab53533, "clmulh a0, a0, a1"},
+		{0x0ab52533, "clmulr a0, a0, a1"},
+		// Zbkb, with zext.h still shown for pack with zero
+		{0x08b54533, "pack a0, a0, a1"},
+		{0x08b57533, "packh a0, a0, a1"},
+		{0x08054533, "zext.h a0, a0"},
+		{0x68755513, "brev8 a0, a0"},
+		{0x08f51513, "zip a0, a0"},
+		{0x08f55513, "unzip a0, a0"},
+		// Zcmp
+		{0xb842, "cm.push {ra}, -16"},
+		{0xb862, "cm.push {ra, s0-s1}, -16"},
+		{0xb8f6, "cm.push {ra, s0-s11}, -80"},
+		{0xba52, "cm.pop {ra, s0}, 16"},
+		{0xbe42, "cm.popret {ra}, 16"},
+		{0xbcb2, "cm.popretz {ra, s0-s6}, 32"},
+		{0xacaa, "cm.mvsa01 s1, s2"},
+		{0xacea, "cm.mva01s s1, s2"},
+		{0xaca6, ".2byte 0xaca6"}, // cm.mvsa01 needs two different registers
+		{0xb802, ".2byte 0xb802"}, // rlist 0-3 is reserved
+	}
+
+	for _, tt := range tests {
+		size := 4
+		if tt.raw&3 != 3 {
+			size = 2
+		}
+		data := binary.LittleEndian.AppendUint32(nil, tt.raw)[:size]
+		insts := DecodeRISCV(data, 0x20000000)
+		if len(insts) != 1 {
+			t.Errorf("0x%08x: decoded %d instructions, want 1", tt.raw, len(insts))
+			continue
+		}
+		if got := insts[0].Mnemonic + " " + insts[0].Operands; got != tt.want {
+			t.Errorf("0x%08x: got %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
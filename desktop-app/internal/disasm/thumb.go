@@ -0,0 +1,951 @@
+package disasm
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// Decoder for the Thumb instruction set as used by Cortex-M0+ (ARMv6-M, RP2040)
+// and the integer subset of Thumb-2 used by Cortex-M33 (ARMv8-M Mainline, RP2350).
+// Floating point and coprocessor instructions are shown as raw .inst.w words.
+
+var armRegs = [16]string{"r0", "r1", "r2", "r3", "r4", "r5", "r6", "r7",
+	"r8", "r9", "r10", "r11", "r12", "sp", "lr", "pc"}
+
+var armConds = [16]string{"eq", "ne", "cs", "cc", "mi", "pl", "vs", "vc",
+	"hi", "ls", "ge", "lt", "gt", "le", "", ""}
+
+var armShifts = [4]string{"lsl", "lsr", "asr", "ror"}
+
+// DecodeThumb decodes Thumb/Thumb-2 code. data is little-endian as read from
+// memory and addr is the address of data[0] (must be halfword aligned).
+func DecodeThumb(data []byte, addr uint32) []Instruction {
+	var insts []Instruction
+	var itState uint32 // firstcond:mask of an IT block in progress, as in ITSTATE
+
+	for i := 0; i+1 < len(data); {
+		hw1 := binary.LittleEndian.Uint16(data[i:])
+		pc := addr + uint32(i)
+
+		// Instructions in an IT block take their condition from it
+		cond, inIT := itState>>4, itState&0xF != 0
+		if itState&7 == 0 {
+			itState = 0
+		} else {
+			itState = itState&0xE0 | (itState<<1)&0x1F
+		}
+
+		if isThumb32(hw1) {
+			if i+3 >= len(data) {
+				// First half of a 32-bit instruction at the end of the buffer
+				insts = append(insts, Instruction{Address: pc, Size: 2, Raw: uint32(hw1), Mnemonic: ".short", Operands: fmt.Sprintf("0x%04x", hw1), Halfwords: true})
+				break
+			}
+			hw2 := binary.LittleEndian.Uint16(data[i+2:])
+			inst := decodeThumb32(hw1, hw2, pc)
+			inst.Address = pc
+			inst.Size = 4
+			inst.Raw = uint32(hw1)<<16 | uint32(hw2)
+			inst.Halfwords = true
+			if inIT {
+				inst = conditional(inst, cond, false)
+			}
+			insts = append(insts, inst)
+			i += 4
+			continue
+		}
+
+		inst := decodeThumb16(hw1, pc)
+		inst.Address = pc
+		inst.Size = 2
+		inst.Raw = uint32(hw1)
+		inst.Halfwords = true
+		if inIT {
+			inst = conditional(inst, cond, true)
+		} else if hw1&0xFF00 == 0xBF00 && hw1&0xF != 0 {
+			itState = uint32(hw1) & 0xFF
+		}
+		insts = append(insts, inst)
+		i += 2
+	}
+
+	return insts
+}
+
+// isThumb32 reports whether the halfword starts a 32-bit instruction
+func isThumb32(hw uint16) bool {
+	return hw>>11 == 0x1D || hw>>11 == 0x1E || hw>>11 == 0x1F
+}
+
+func op(mnemonic, format string, args ...any) Instruction {
+	return Instruction{Mnemonic: mnemonic, Operands: fmt.Sprintf(format, args...)}
+}
+
+func branch(mnemonic string, target uint32) Instruction {
+	return Instruction{Mnemonic: mnemonic, Operands: fmt.Sprintf("0x%08x", target), Target: target, HasTarget: true, IsBranch: true}
+}
+
+func literal(inst Instruction, target uint32) Instruction {
+	inst.Target = target
+	inst.HasTarget = true
+	return inst
+}
+
+func signExtend(val uint32, bits uint) int32 {
+	shift := 32 - bits
+	return int32(val<<shift) >> shift
+}
+
+// regList formats a register list bitmask as "{r0, r4, lr}"
+func regList(mask uint32) string {
+	var regs []string
+	for r := 0; r < 16; r++ {
+		if mask&(1<<r) != 0 {
+			regs = append(regs, armRegs[r])
+		}
+	}
+	return "{" + strings.Join(regs, ", ") + "}"
+}
+
+// imm formats an immediate operand, in hex once it stops being a small number
+func imm(val uint32) string {
+	if val < 256 {
+		return fmt.Sprintf("#%d", val)
+	}
+	return fmt.Sprintf("#0x%x", val)
+}
+
+func decodeThumb16(hw uint16, pc uint32) Instruction {
+	h := uint32(hw)
+	rd := h & 7
+	rn := (h >> 3) & 7
+	rm := (h >> 6) & 7
+
+	switch {
+	// Shift (immediate), add, subtract, move, and compare
+	case h>>11 == 0x03:
+		switch (h >> 9) & 3 {
+		case 0:
+			return op("adds", "%s, %s, %s", armRegs[rd], armRegs[rn], armRegs[rm])
+		case 1:
+			return op("subs", "%s, %s, %s", armRegs[rd], armRegs[rn], armRegs[rm])
+		case 2:
+			return op("adds", "%s, %s, #%d", armRegs[rd], armRegs[rn], rm)
+		default:
+			return op("subs", "%s, %s, #%d", armRegs[rd], armRegs[rn], rm)
+		}
+	case h>>13 == 0:
+		shift := (h >> 6) & 0x1F
+		switch (h >> 11) & 3 {
+		case 0:
+			if shift == 0 {
+				return op("movs", "%s, %s", armRegs[rd], armRegs[rn])
+			}
+			return op("lsls", "%s, %s, #%d", armRegs[rd], armRegs[rn], shift)
+		case 1:
+			if shift == 0 {
+				shift = 32
+			}
+			return op("lsrs", "%s, %s, #%d", armRegs[rd], armRegs[rn], shift)
+		default:
+			if shift == 0 {
+				shift = 32
+			}
+			return op("asrs", "%s, %s, #%d", armRegs[rd], armRegs[rn], shift)
+		}
+	case h>>13 == 1:
+		reg := armRegs[(h>>8)&7]
+		imm8 := h & 0xFF
+		mnemonics := [4]string{"movs", "cmp", "adds", "subs"}
+		return op(mnemonics[(h>>11)&3], "%s, %s", reg, imm(imm8))
+
+	// Data processing
+	case h>>10 == 0x10:
+		mnemonics := [16]string{"ands", "eors", "lsls", "lsrs", "asrs", "adcs", "sbcs", "rors",
+			"tst", "rsbs", "cmp", "cmn", "orrs", "muls", "bics", "mvns"}
+		opcode := (h >> 6) & 0xF
+		switch opcode {
+		case 9:
+			return op("rsbs", "%s, %s, #0", armRegs[rd], armRegs[rn])
+		case 13:
+			return op("muls", "%s, %s, %s", armRegs[rd], armRegs[rn], armRegs[rd])
+		}
+		return op(mnemonics[opcode], "%s, %s", armRegs[rd], armRegs[rn])
+
+	// Special data instructions and branch and exchange
+	case h>>10 == 0x11:
+		rdn := (h>>4)&8 | rd
+		rm4 := (h >> 3) & 0xF
+		switch (h >> 8) & 3 {
+		case 0:
+			return op("add", "%s, %s", armRegs[rdn], armRegs[rm4])
+		case 1:
+			return op("cmp", "%s, %s", armRegs[rdn], armRegs[rm4])
+		case 2:
+			if rdn == 8 && rm4 == 8 {
+				return op("nop", "")
+			}
+			return op("mov", "%s, %s", armRegs[rdn], armRegs[rm4])
+		default:
+			if h&0x80 != 0 {
+				return op("blx", "%s", armRegs[rm4])
+			}
+			return op("bx", "%s", armRegs[rm4])
+		}
+
+	// Load from literal pool
+	case h>>11 == 0x09:
+		offset := (h & 0xFF) * 4
+		inst := op("ldr", "%s, [pc, #%d]", armRegs[(h>>8)&7], offset)
+		return literal(inst, (pc+4)&^3+offset)
+
+	// Load/store register offset
+	case h>>12 == 0x5:
+		mnemonics := [8]string{"str", "strh", "strb", "ldrsb", "ldr", "ldrh", "ldrb", "ldrsh"}
+		return op(mnemonics[(h>>9)&7], "%s, [%s, %s]", armRegs[rd], armRegs[rn], armRegs[rm])
+
+	// Load/store word/byte immediate offset
+	case h>>13 == 0x3:
+		offset := (h >> 6) & 0x1F
+		mnemonic := "str"
+		if h&0x800 != 0 {
+			mnemonic = "ldr"
+		}
+		if h&0x1000 != 0 {
+			mnemonic += "b"
+		} else {
+			offset *= 4
+		}
+		return op(mnemonic, "%s, [%s, #%d]", armRegs[rd], armRegs[rn], offset)
+
+	// Load/store halfword immediate offset
+	case h>>12 == 0x8:
+		offset := ((h >> 6) & 0x1F) * 2
+		mnemonic := "strh"
+		if h&0x800 != 0 {
+			mnemonic = "ldrh"
+		}
+		return op(mnemonic, "%s, [%s, #%d]", armRegs[rd], armRegs[rn], offset)
+
+	// Load/store SP-relative
+	case h>>12 == 0x9:
+		mnemonic := "str"
+		if h&0x800 != 0 {
+			mnemonic = "ldr"
+		}
+		return op(mnemonic, "%s, [sp, #%d]", armRegs[(h>>8)&7], (h&0xFF)*4)
+
+	// Generate PC-relative address
+	case h>>11 == 0x14:
+		offset := (h & 0xFF) * 4
+		inst := op("adr", "%s, #%d", armRegs[(h>>8)&7], offset)
+		return literal(inst, (pc+4)&^3+offset)
+
+	// Generate SP-relative address
+	case h>>11 == 0x15:
+		return op("add", "%s, sp, #%d", armRegs[(h>>8)&7], (h&0xFF)*4)
+
+	// Miscellaneous 16-bit instructions
+	case h>>12 == 0xB:
+		return decodeThumb16Misc(h, pc)
+
+	// Store/load multiple
+	case h>>11 == 0x18:
+		return op("stmia", "%s!, %s", armRegs[(h>>8)&7], regList(h&0xFF))
+	case h>>11 == 0x19:
+		base := (h >> 8) & 7
+		if h&(1<<base) != 0 {
+			return op("ldmia", "%s, %s", armRegs[base], regList(h&0xFF))
+		}
+		return op("ldmia", "%s!, %s", armRegs[base], regList(h&0xFF))
+
+	// Conditional branch, UDF and SVC
+	case h>>12 == 0xD:
+		cond := (h >> 8) & 0xF
+		switch cond {
+		case 0xE:
+			return op("udf", "#%d", h&0xFF)
+		case 0xF:
+			return op("svc", "%d", h&0xFF)
+		}
+		target := uint32(int32(pc+4) + signExtend((h&0xFF)<<1, 9))
+		return branch("b"+armConds[cond], target)
+
+	// Unconditional branch
+	case h>>11 == 0x1C:
+		target := uint32(int32(pc+4) + signExtend((h&0x7FF)<<1, 12))
+		return branch("b", target)
+	}
+
+	return op(".short", "0x%04x", h)
+}
+
+func decodeThumb16Misc(h uint32, pc uint32) Instruction {
+	switch {
+	case h&0xFF80 == 0xB000:
+		return op("add", "sp, #%d", (h&0x7F)*4)
+	case h&0xFF80 == 0xB080:
+		return op("sub", "sp, #%d", (h&0x7F)*4)
+
+	// Compare and branch on (non-)zero, ARMv7-M and later
+	case h&0xF500 == 0xB100:
+		offset := ((h>>9)&1)<<6 | ((h>>3)&0x1F)<<1
+		mnemonic := "cbz"
+		if h&0x800 != 0 {
+			mnemonic = "cbnz"
+		}
+		inst := branch(mnemonic, pc+4+offset)
+		inst.Operands = fmt.Sprintf("%s, 0x%08x", armRegs[h&7], inst.Target)
+		return inst
+
+	case h&0xFF00 == 0xB200:
+		mnemonics := [4]string{"sxth", "sxtb", "uxth", "uxtb"}
+		return op(mnemonics[(h>>6)&3], "%s, %s", armRegs[h&7], armRegs[(h>>3)&7])
+	case h&0xFE00 == 0xB400:
+		mask := h & 0xFF
+		if h&0x100 != 0 {
+			mask |= 1 << 14 // LR
+		}
+		return op("push", "%s", regList(mask))
+	case h == 0xB662:
+		return op("cpsie", "i")
+	case h == 0xB672:
+		return op("cpsid", "i")
+	case h == 0xB661:
+		return op("cpsie", "f")
+	case h == 0xB671:
+		return op("cpsid", "f")
+	case h&0xFF00 == 0xBA00:
+		rd, rm := armRegs[h&7], armRegs[(h>>3)&7]
+		switch (h >> 6) & 3 {
+		case 0:
+			return op("rev", "%s, %s", rd, rm)
+		case 1:
+			return op("rev16", "%s, %s", rd, rm)
+		case 3:
+			return op("revsh", "%s, %s", rd, rm)
+		}
+	case h&0xFE00 == 0xBC00:
+		mask := h & 0xFF
+		if h&0x100 != 0 {
+			mask |= 1 << 15 // PC
+		}
+		return op("pop", "%s", regList(mask))
+	case h&0xFF00 == 0xBE00:
+		return op("bkpt", "0x%04x", h&0xFF)
+
+	// If-Then and hints
+	case h&0xFF00 == 0xBF00:
+		if h&0xF != 0 {
+			return op(itMnemonic(h), "%s", armConds[(h>>4)&0xF])
+		}
+		hints := map[uint32]string{0: "nop", 1: "yield", 2: "wfe", 3: "wfi", 4: "sev"}
+		if name, ok := hints[(h>>4)&0xF]; ok {
+			return op(name, "")
+		}
+	}
+
+	return op(".short", "0x%04x", h)
+}
+
+// itMnemonic returns "it", "itt", "ite", ... for an IT instruction
+func itMnemonic(h uint32) string {
+	firstCond := (h >> 4) & 0xF
+	mask := h & 0xF
+
+	// The lowest set bit of the mask terminates the pattern
+	size := 4
+	for mask&(1<<(4-size)) == 0 {
+		size--
+	}
+
+	mnemonic := "it"
+	for i := 1; i < size; i++ {
+		bit := (mask >> (4 - i)) & 1
+		if bit == firstCond&1 {
+			mnemonic += "t"
+		} else {
+			mnemonic += "e"
+		}
+	}
+	return mnemonic
+}
+
+// narrowFlagSetting are the 16-bit instructions that set the flags outside an
+// IT block and don't inside one
+var narrowFlagSetting = map[string]bool{
+	"adds": true, "subs": true, "movs": true, "lsls": true, "lsrs": true, "asrs": true,
+	"ands": true, "eors": true, "adcs": true, "sbcs": true, "rors": true, "rsbs": true,
+	"orrs": true, "muls": true, "bics": true, "mvns": true,
+}
+
+// conditional adds an IT block's condition to an instruction, before any .w
+// qualifier: "moveq", "addne.w"
+func conditional(inst Instruction, cond uint32, narrow bool) Instruction {
+	if strings.HasPrefix(inst.Mnemonic, ".") {
+		return inst
+	}
+	mnemonic, qualifier, wide := strings.Cut(inst.Mnemonic, ".")
+	if narrow && narrowFlagSetting[mnemonic] {
+		mnemonic = strings.TrimSuffix(mnemonic, "s")
+	}
+	mnemonic += armConds[cond]
+	if wide {
+		mnemonic += "." + qualifier
+	}
+	inst.Mnemonic = mnemonic
+	return inst
+}
+
+// thumbExpandImm expands a Thumb-2 modified immediate constant
+func thumbExpandImm(imm12 uint32) uint32 {
+	if imm12>>10 == 0 {
+		imm8 := imm12 & 0xFF
+		switch (imm12 >> 8) & 3 {
+		case 0:
+			return imm8
+		case 1:
+			return imm8<<16 | imm8
+		case 2:
+			return imm8<<24 | imm8<<8
+		default:
+			return imm8<<24 | imm8<<16 | imm8<<8 | imm8
+		}
+	}
+	unrotated := 0x80 | imm12&0x7F
+	rotation := imm12 >> 7
+	return unrotated>>rotation | unrotated<<(32-rotation)
+}
+
+func decodeThumb32(hw1, hw2 uint16, pc uint32) Instruction {
+	h1, h2 := uint32(hw1), uint32(hw2)
+	rn := h1 & 0xF
+	rd := (h2 >> 8) & 0xF
+	rt := (h2 >> 12) & 0xF
+
+	switch {
+	// Branches and miscellaneous control
+	case h1>>11 == 0x1E && h2&0x8000 != 0:
+		return decodeThumb32Branch(h1, h2, pc)
+
+	// Data processing (modified immediate)
+	case h1&0xFA00 == 0xF000 && h2&0x8000 == 0:
+		return decodeThumb32ModifiedImm(h1, h2)
+
+	// Data processing (plain binary immediate)
+	case h1&0xFA00 == 0xF200 && h2&0x8000 == 0:
+		return decodeThumb32PlainImm(h1, h2, pc)
+
+	// Load/store multiple
+	case h1&0xFE40 == 0xE800:
+		return decodeThumb32Multiple(h1, h2)
+
+	// Load/store dual, exclusive, table branch
+	case h1&0xFE40 == 0xE840:
+		// LDRD/STRD use pre- or post-indexing, the rest don't
+		if h1&0x120 != 0 {
+			mnemonic := "strd"
+			if h1&0x10 != 0 {
+				mnemonic = "ldrd"
+			}
+			return op(mnemonic, "%s, %s, %s", armRegs[rt], armRegs[rd], indexedAddress(rn, (h2&0xFF)*4, h1&0x100 != 0, h1&0x80 != 0, h1&0x20 != 0))
+		}
+		switch {
+		case h1&0xFFF0 == 0xE8D0 && h2&0xFFE0 == 0xF000:
+			if h2&0x10 != 0 {
+				return op("tbh", "[%s, %s, lsl #1]", armRegs[rn], armRegs[h2&0xF])
+			}
+			return op("tbb", "[%s, %s]", armRegs[rn], armRegs[h2&0xF])
+		case h1&0xFFF0 == 0xE850:
+			return op("ldrex", "%s, [%s, #%d]", armRegs[rt], armRegs[rn], (h2&0xFF)*4)
+		case h1&0xFFF0 == 0xE840:
+			return op("strex", "%s, %s, [%s, #%d]", armRegs[rd], armRegs[rt], armRegs[rn], (h2&0xFF)*4)
+		}
+
+	// Data processing (shifted register)
+	case h1&0xFE00 == 0xEA00:
+		return decodeThumb32ShiftedReg(h1, h2)
+
+	// Load/store single data item
+	case h1&0xFE00 == 0xF800:
+		return decodeThumb32LoadStore(h1, h2, pc)
+
+	// Data processing (register)
+	case h1&0xFF00 == 0xFA00 && h2&0xF000 == 0xF000:
+		return decodeThumb32Register(h1, h2)
+
+	// Multiply, multiply accumulate, and divide
+	case h1&0xFF80 == 0xFB00:
+		return decodeThumb32Multiply(h1, h2)
+	case h1&0xFF80 == 0xFB80:
+		return decodeThumb32LongMultiply(h1, h2)
+	}
+
+	return op(".inst.w", "0x%04x%04x", h1, h2)
+}
+
+func decodeThumb32Branch(h1, h2, pc uint32) Instruction {
+	s := (h1 >> 10) & 1
+	j1 := (h2 >> 13) & 1
+	j2 := (h2 >> 11) & 1
+
+	switch {
+	// BL and B.W (T4)
+	case h2&0x5000 == 0x5000 || h2&0x5000 == 0x1000:
+		i1 := ^(j1 ^ s) & 1
+		i2 := ^(j2 ^ s) & 1
+		offset := s<<24 | i1<<23 | i2<<22 | (h1&0x3FF)<<12 | (h2&0x7FF)<<1
+		target := uint32(int32(pc+4) + signExtend(offset, 25))
+		if h2&0x4000 != 0 {
+			return branch("bl", target)
+		}
+		return branch("b.w", target)
+
+	// Conditional branch (T3)
+	case h2&0x5000 == 0x0000 && (h1>>6)&0xE != 0xE:
+		cond := (h1 >> 6) & 0xF
+		offset := s<<20 | j2<<19 | j1<<18 | (h1&0x3F)<<12 | (h2&0x7FF)<<1
+		target := uint32(int32(pc+4) + signExtend(offset, 21))
+		return branch("b"+armConds[cond]+".w", target)
+
+	// MSR
+	case h1&0xFFE0 == 0xF380 && h2&0xD000 == 0x8000:
+		return op("msr", "%s, %s", specialReg(h2&0xFF), armRegs[h1&0xF])
+
+	// MRS
+	case h1&0xFFEF == 0xF3EF && h2&0xD000 == 0x8000:
+		return op("mrs", "%s, %s", armRegs[(h2>>8)&0xF], specialReg(h2&0xFF))
+
+	// Barriers
+	case h1 == 0xF3BF && h2&0xFFF0 == 0x8F40:
+		return op("dsb", "%s", barrierOption(h2&0xF))
+	case h1 == 0xF3BF && h2&0xFFF0 == 0x8F50:
+		return op("dmb", "%s", barrierOption(h2&0xF))
+	case h1 == 0xF3BF && h2&0xFFF0 == 0x8F60:
+		return op("isb", "%s", barrierOption(h2&0xF))
+
+	// Hints
+	case h1 == 0xF3AF && h2&0xFF00 == 0x8000:
+		hints := map[uint32]string{0: "nop.w", 1: "yield.w", 2: "wfe.w", 3: "wfi.w", 4: "sev.w"}
+		if name, ok := hints[h2&0xFF]; ok {
+			return op(name, "")
+		}
+
+	// Permanently undefined
+	case h1&0xFFF0 == 0xF7F0 && h2&0xF000 == 0xA000:
+		return op("udf.w", "#%d", (h1&0xF)<<12|h2&0xFFF)
+	}
+
+	return op(".inst.w", "0x%04x%04x", h1, h2)
+}
+
+func specialReg(sysm uint32) string {
+	names := map[uint32]string{
+		0: "apsr", 1: "iapsr", 2: "eapsr", 3: "xpsr", 5: "ipsr", 6: "epsr", 7: "iepsr",
+		8: "msp", 9: "psp", 10: "msplim", 11: "psplim",
+		16: "primask", 17: "basepri", 18: "basepri_max", 19: "faultmask", 20: "control",
+	}
+	if name, ok := names[sysm]; ok {
+		return name
+	}
+	return fmt.Sprintf("sysm_%d", sysm)
+}
+
+func barrierOption(option uint32) string {
+	if option == 0xF {
+		return "sy"
+	}
+	return fmt.Sprintf("#%d", option)
+}
+
+func decodeThumb32ModifiedImm(h1, h2 uint32) Instruction {
+	opcode := (h1 >> 5) & 0xF
+	setFlags := h1&0x10 != 0
+	rn := h1 & 0xF
+	rd := (h2 >> 8) & 0xF
+	value := thumbExpandImm((h1>>10)&1<<11 | (h2>>12)&7<<8 | h2&0xFF)
+
+	suffix := ""
+	if setFlags {
+		suffix = "s"
+	}
+
+	switch opcode {
+	case 0x0:
+		if rd == 15 && setFlags {
+			return op("tst.w", "%s, %s", armRegs[rn], imm(value))
+		}
+		return op("and"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(value))
+	case 0x1:
+		return op("bic"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(value))
+	case 0x2:
+		if rn == 15 {
+			return op("mov"+suffix+".w", "%s, %s", armRegs[rd], imm(value))
+		}
+		return op("orr"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(value))
+	case 0x3:
+		if rn == 15 {
+			return op("mvn"+suffix+".w", "%s, %s", armRegs[rd], imm(value))
+		}
+		return op("orn"+suffix, "%s, %s, %s", armRegs[rd], armRegs[rn], imm(value))
+	case 0x4:
+		if rd == 15 && setFlags {
+			return op("teq.w", "%s, %s", armRegs[rn], imm(value))
+		}
+		return op("eor"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(value))
+	case 0x8:
+		if rd == 15 && setFlags {
+			return op("cmn.w", "%s, %s", armRegs[rn], imm(value))
+		}
+		return op("add"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(value))
+	case 0xA:
+		return op("adc"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(value))
+	case 0xB:
+		return op("sbc"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(value))
+	case 0xD:
+		if rd == 15 && setFlags {
+			return op("cmp.w", "%s, %s", armRegs[rn], imm(value))
+		}
+		return op("sub"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(value))
+	case 0xE:
+		return op("rsb"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(value))
+	}
+
+	return op(".inst.w", "0x%04x%04x", h1, h2)
+}
+
+func decodeThumb32PlainImm(h1, h2, pc uint32) Instruction {
+	opcode := (h1 >> 4) & 0x1F
+	rn := h1 & 0xF
+	rd := (h2 >> 8) & 0xF
+	imm12 := (h1>>10)&1<<11 | (h2>>12)&7<<8 | h2&0xFF
+	imm16 := (h1&0xF)<<12 | imm12
+
+	switch opcode {
+	case 0x00:
+		if rn == 15 {
+			inst := op("adr.w", "%s, #%d", armRegs[rd], imm12)
+			return literal(inst, (pc+4)&^3+imm12)
+		}
+		return op("addw", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(imm12))
+	case 0x04:
+		return op("movw", "%s, %s", armRegs[rd], imm(imm16))
+	case 0x0A:
+		if rn == 15 {
+			inst := op("adr.w", "%s, #-%d", armRegs[rd], imm12)
+			return literal(inst, (pc+4)&^3-imm12)
+		}
+		return op("subw", "%s, %s, %s", armRegs[rd], armRegs[rn], imm(imm12))
+	case 0x0C:
+		return op("movt", "%s, %s", armRegs[rd], imm(imm16))
+	}
+
+	// Bitfield and saturate instructions
+	lsb := (h2>>12)&7<<2 | (h2>>6)&3
+	widthField := h2 & 0x1F
+	switch opcode {
+	case 0x14:
+		return op("sbfx", "%s, %s, #%d, #%d", armRegs[rd], armRegs[rn], lsb, widthField+1)
+	case 0x16:
+		if rn == 15 {
+			return op("bfc", "%s, #%d, #%d", armRegs[rd], lsb, widthField-lsb+1)
+		}
+		return op("bfi", "%s, %s, #%d, #%d", armRegs[rd], armRegs[rn], lsb, widthField-lsb+1)
+	case 0x1C:
+		return op("ubfx", "%s, %s, #%d, #%d", armRegs[rd], armRegs[rn], lsb, widthField+1)
+	case 0x10, 0x12:
+		return op("ssat", "%s, #%d, %s", armRegs[rd], widthField+1, armRegs[rn])
+	case 0x18, 0x1A:
+		return op("usat", "%s, #%d, %s", armRegs[rd], widthField, armRegs[rn])
+	}
+
+	return op(".inst.w", "0x%04x%04x", h1, h2)
+}
+
+func decodeThumb32Multiple(h1, h2 uint32) Instruction {
+	rn := h1 & 0xF
+	writeback := h1&0x20 != 0
+	load := h1&0x10 != 0
+	mask := h2 & 0xFFFF
+
+	base := armRegs[rn]
+	if writeback {
+		base += "!"
+	}
+
+	switch (h1 >> 7) & 3 {
+	case 1: // Increment after
+		if load {
+			if rn == 13 && writeback {
+				return op("pop.w", "%s", regList(mask))
+			}
+			return op("ldmia.w", "%s, %s", base, regList(mask))
+		}
+		return op("stmia.w", "%s, %s", base, regList(mask))
+	case 2: // Decrement before
+		if load {
+			return op("ldmdb", "%s, %s", base, regList(mask))
+		}
+		if rn == 13 && writeback {
+			return op("push.w", "%s", regList(mask))
+		}
+		return op("stmdb", "%s, %s", base, regList(mask))
+	}
+
+	return op(".inst.w", "0x%04x%04x", h1, h2)
+}
+
+// indexedAddress formats [rn, #off], [rn, #off]! or [rn], #off
+func indexedAddress(rn, offset uint32, index, add, writeback bool) string {
+	sign := ""
+	if !add {
+		sign = "-"
+	}
+	switch {
+	case !index:
+		return fmt.Sprintf("[%s], #%s%d", armRegs[rn], sign, offset)
+	case writeback:
+		return fmt.Sprintf("[%s, #%s%d]!", armRegs[rn], sign, offset)
+	case offset == 0:
+		return fmt.Sprintf("[%s]", armRegs[rn])
+	default:
+		return fmt.Sprintf("[%s, #%s%d]", armRegs[rn], sign, offset)
+	}
+}
+
+func decodeThumb32ShiftedReg(h1, h2 uint32) Instruction {
+	opcode := (h1 >> 5) & 0xF
+	setFlags := h1&0x10 != 0
+	rn := h1 & 0xF
+	rd := (h2 >> 8) & 0xF
+	rm := h2 & 0xF
+	shiftType := (h2 >> 4) & 3
+	amount := (h2>>12)&7<<2 | (h2>>6)&3
+
+	suffix := ""
+	if setFlags {
+		suffix = "s"
+	}
+
+	// Shifted operand, e.g. "r3, lsl #2"
+	operand := armRegs[rm]
+	switch {
+	case shiftType == 3 && amount == 0:
+		operand += ", rrx"
+	case amount != 0 || shiftType != 0:
+		if amount == 0 {
+			amount = 32
+		}
+		operand += fmt.Sprintf(", %s #%d", armShifts[shiftType], amount)
+	}
+
+	switch opcode {
+	case 0x0:
+		if rd == 15 && setFlags {
+			return op("tst.w", "%s, %s", armRegs[rn], operand)
+		}
+		return op("and"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], operand)
+	case 0x1:
+		return op("bic"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], operand)
+	case 0x2:
+		if rn == 15 {
+			// MOV and the immediate shifts
+			if amount == 0 && shiftType == 0 {
+				return op("mov"+suffix+".w", "%s, %s", armRegs[rd], armRegs[rm])
+			}
+			if shiftType == 3 && (h2>>12)&7 == 0 && (h2>>6)&3 == 0 {
+				return op("rrx"+suffix, "%s, %s", armRegs[rd], armRegs[rm])
+			}
+			return op(armShifts[shiftType]+suffix+".w", "%s, %s, #%d", armRegs[rd], armRegs[rm], amount)
+		}
+		return op("orr"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], operand)
+	case 0x3:
+		if rn == 15 {
+			return op("mvn"+suffix+".w", "%s, %s", armRegs[rd], operand)
+		}
+		return op("orn"+suffix, "%s, %s, %s", armRegs[rd], armRegs[rn], operand)
+	case 0x4:
+		if rd == 15 && setFlags {
+			return op("teq.w", "%s, %s", armRegs[rn], operand)
+		}
+		return op("eor"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], operand)
+	case 0x8:
+		if rd == 15 && setFlags {
+			return op("cmn.w", "%s, %s", armRegs[rn], operand)
+		}
+		return op("add"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], operand)
+	case 0xA:
+		return op("adc"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], operand)
+	case 0xB:
+		return op("sbc"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], operand)
+	case 0xD:
+		if rd == 15 && setFlags {
+			return op("cmp.w", "%s, %s", armRegs[rn], operand)
+		}
+		return op("sub"+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], operand)
+	case 0xE:
+		return op("rsb"+suffix, "%s, %s, %s", armRegs[rd], armRegs[rn], operand)
+	}
+
+	return op(".inst.w", "0x%04x%04x", h1, h2)
+}
+
+func decodeThumb32LoadStore(h1, h2, pc uint32) Instruction {
+	rn := h1 & 0xF
+	rt := (h2 >> 12) & 0xF
+	load := h1&0x10 != 0
+	signed := h1&0x100 != 0
+
+	var mnemonic string
+	switch (h1 >> 5) & 3 {
+	case 0:
+		mnemonic = "b"
+	case 1:
+		mnemonic = "h"
+	case 2:
+		mnemonic = ""
+	default:
+		return op(".inst.w", "0x%04x%04x", h1, h2)
+	}
+	if signed {
+		mnemonic = "s" + mnemonic
+	}
+	if load {
+		mnemonic = "ldr" + mnemonic
+	} else {
+		mnemonic = "str" + mnemonic
+	}
+
+	// Preload hints are loads to PC
+	if load && rt == 15 && (h1>>5)&3 != 2 {
+		mnemonic = "pld"
+		if signed {
+			mnemonic = "pli"
+		}
+	}
+
+	switch {
+	// PC-relative literal
+	case load && rn == 15:
+		offset := h2 & 0xFFF
+		target := (pc + 4) &^ 3
+		sign := ""
+		if h1&0x80 != 0 {
+			target += offset
+		} else {
+			target -= offset
+			sign = "-"
+		}
+		inst := op(mnemonic+".w", "%s, [pc, #%s%d]", armRegs[rt], sign, offset)
+		return literal(inst, target)
+
+	// 12-bit positive immediate offset
+	case h1&0x80 != 0:
+		return op(mnemonic+".w", "%s, %s", armRegs[rt], indexedAddress(rn, h2&0xFFF, true, true, false))
+
+	// 8-bit immediate offset with pre/post indexing
+	case h2&0x800 != 0:
+		index := h2&0x400 != 0
+		add := h2&0x200 != 0
+		writeback := h2&0x100 != 0
+		if index && add && !writeback {
+			mnemonic += "t" // Unprivileged access
+		}
+		return op(mnemonic, "%s, %s", armRegs[rt], indexedAddress(rn, h2&0xFF, index, add, writeback))
+
+	// Register offset
+	case h2&0xFC0 == 0:
+		rm := h2 & 0xF
+		shift := (h2 >> 4) & 3
+		if shift == 0 {
+			return op(mnemonic+".w", "%s, [%s, %s]", armRegs[rt], armRegs[rn], armRegs[rm])
+		}
+		return op(mnemonic+".w", "%s, [%s, %s, lsl #%d]", armRegs[rt], armRegs[rn], armRegs[rm], shift)
+	}
+
+	return op(".inst.w", "0x%04x%04x", h1, h2)
+}
+
+func decodeThumb32Register(h1, h2 uint32) Instruction {
+	rn := h1 & 0xF
+	rd := (h2 >> 8) & 0xF
+	rm := h2 & 0xF
+	suffix := ""
+	if h1&0x10 != 0 {
+		suffix = "s"
+	}
+
+	switch {
+	// Shift by register
+	case h1&0xFF80 == 0xFA00 && h2&0xF0 == 0:
+		return op(armShifts[(h1>>5)&3]+suffix+".w", "%s, %s, %s", armRegs[rd], armRegs[rn], armRegs[rm])
+
+	// Sign/zero extend
+	case h1&0xFF80 == 0xFA00 && rn == 15 && h2&0x80 != 0:
+		mnemonics := map[uint32]string{0: "sxth.w", 1: "uxth.w", 4: "sxtb.w", 5: "uxtb.w"}
+		if name, ok := mnemonics[(h1>>4)&7]; ok {
+			rotate := (h2 >> 4) & 3
+			if rotate == 0 {
+				return op(name, "%s, %s", armRegs[rd], armRegs[rm])
+			}
+			return op(name, "%s, %s, ror #%d", armRegs[rd], armRegs[rm], rotate*8)
+		}
+
+	// Miscellaneous operations
+	case h1&0xFFF0 == 0xFA90 && h2&0xF0 == 0x80:
+		return op("rev.w", "%s, %s", armRegs[rd], armRegs[rm])
+	case h1&0xFFF0 == 0xFA90 && h2&0xF0 == 0x90:
+		return op("rev16.w", "%s, %s", armRegs[rd], armRegs[rm])
+	case h1&0xFFF0 == 0xFA90 && h2&0xF0 == 0xA0:
+		return op("rbit", "%s, %s", armRegs[rd], armRegs[rm])
+	case h1&0xFFF0 == 0xFA90 && h2&0xF0 == 0xB0:
+		return op("revsh.w", "%s, %s", armRegs[rd], armRegs[rm])
+	case h1&0xFFF0 == 0xFAB0 && h2&0xF0 == 0x80:
+		return op("clz", "%s, %s", armRegs[rd], armRegs[rm])
+	}
+
+	return op(".inst.w", "0x%04x%04x", h1, h2)
+}
+
+func decodeThumb32Multiply(h1, h2 uint32) Instruction {
+	rn := h1 & 0xF
+	ra := (h2 >> 12) & 0xF
+	rd := (h2 >> 8) & 0xF
+	rm := h2 & 0xF
+
+	if (h1>>4)&7 == 0 {
+		switch (h2 >> 4) & 3 {
+		case 0:
+			if ra == 15 {
+				return op("mul.w", "%s, %s, %s", armRegs[rd], armRegs[rn], armRegs[rm])
+			}
+			return op("mla", "%s, %s, %s, %s", armRegs[rd], armRegs[rn], armRegs[rm], armRegs[ra])
+		case 1:
+			return op("mls", "%s, %s, %s, %s", armRegs[rd], armRegs[rn], armRegs[rm], armRegs[ra])
+		}
+	}
+
+	return op(".inst.w", "0x%04x%04x", h1, h2)
+}
+
+func decodeThumb32LongMultiply(h1, h2 uint32) Instruction {
+	rn := h1 & 0xF
+	rdLo := (h2 >> 12) & 0xF
+	rdHi := (h2 >> 8) & 0xF
+	rm := h2 & 0xF
+
+	switch {
+	case (h1>>4)&7 == 1 && (h2>>4)&0xF == 0xF:
+		return op("sdiv", "%s, %s, %s", armRegs[rdHi], armRegs[rn], armRegs[rm])
+	case (h1>>4)&7 == 3 && (h2>>4)&0xF == 0xF:
+		return op("udiv", "%s, %s, %s", armRegs[rdHi], armRegs[rn], armRegs[rm])
+	case (h2>>4)&0xF == 0:
+		mnemonics := map[uint32]string{0: "smull", 2: "umull", 4: "smlal", 6: "umlal"}
+		if name, ok := mnemonics[(h1>>4)&7]; ok {
+			return op(name, "%s, %s, %s, %s", armRegs[rdLo], armRegs[rdHi], armRegs[rn], armRegs[rm])
+		}
+	}
+
+	return op(".inst.w", "0x%04x%04x", h1, h2)
+}
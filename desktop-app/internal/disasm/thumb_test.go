@@ -0,0 +1,95 @@
+package disasm
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDecodeThumb(t *testing.T) {
+	tests := []struct {
+		name string
+		code []byte
+		want []string
+	}{
+		// 16-bit
+		{"movs", []byte{0x05, 0x20}, []string{"movs r0, #5"}},
+		{"adds", []byte{0xd1, 0x18}, []string{"adds r1, r2, r3"}},
+		{"push", []byte{0x30, 0xb5}, []string{"push {r4, r5, lr}"}},
+		{"pop", []byte{0x30, 0xbd}, []string{"pop {r4, r5, pc}"}},
+		{"rev", []byte{0x00, 0xba}, []string{"rev r0, r0"}},
+		{"udf", []byte{0x01, 0xde}, []string{"udf #1"}},
+		// 32-bit
+		{"push.w", []byte{0x2d, 0xe9, 0xf0, 0x41}, []string{"push.w {r4, r5, r6, r7, r8, lr}"}},
+		{"movw", []byte{0x42, 0xf2, 0x34, 0x10}, []string{"movw r0, #0x2134"}},
+		{"mov.w", []byte{0x4f, 0xf0, 0xff, 0x20}, []string{"mov.w r0, #0xff00ff00"}},
+		{"ldrb.w", []byte{0x91, 0xf8, 0x04, 0x00}, []string{"ldrb.w r0, [r1, #4]"}},
+		{"ldr.w", []byte{0xd2, 0xf8, 0x00, 0x11}, []string{"ldr.w r1, [r2, #256]"}},
+		{"asr.w", []byte{0x4f, 0xea, 0x21, 0x10}, []string{"asr.w r0, r1, #4"}},
+		{"udiv", []byte{0xb1, 0xfb, 0xf2, 0xf0}, []string{"udiv r0, r1, r2"}},
+		{"udf.w", []byte{0xf0, 0xf7, 0x00, 0xa0}, []string{"udf.w #0"}},
+		// IT blocks: the condition goes on each instruction, and 16-bit ones
+		// stop setting the flags
+		{"itte", []byte{0x06, 0xbf, 0x08, 0x46, 0x40, 0x1c, 0x40, 0x1e, 0x40, 0x1c},
+			[]string{"itte eq", "moveq r0, r1", "addeq r0, r0, #1", "subne r0, r0, #1", "adds r0, r0, #1"}},
+		{"itt with 32-bit", []byte{0xc4, 0xbf, 0x70, 0x47, 0x01, 0xeb, 0x02, 0x00, 0x10, 0x2a},
+			[]string{"itt gt", "bxgt lr", "addgt.w r0, r1, r2", "cmp r2, #16"}},
+		{"it bl", []byte{0x18, 0xbf, 0x00, 0xf0, 0x7e, 0xf8, 0x05, 0x20},
+			[]string{"it ne", "blne 0x10000202", "movs r0, #5"}},
+		// Fallbacks
+		{"undefined 16-bit", []byte{0x00, 0xb6}, []string{".short 0xb600"}},
+		{"floating point", []byte{0x30, 0xee, 0x20, 0x0a}, []string{".inst.w 0xee300a20"}},
+		{"truncated 32-bit", []byte{0x05, 0x20, 0x00, 0xf0}, []string{"movs r0, #5", ".short 0xf000"}},
+	}
+
+	for _, tt := range tests {
+		var got []string
+		for _, inst := range DecodeThumb(tt.code, 0x10000100) {
+			got = append(got, inst.Mnemonic+" "+inst.Operands)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestDecodeThumbTargets(t *testing.T) {
+	tests := []struct {
+		name     string
+		code     []byte
+		addr     uint32
+		want     string
+		target   uint32
+		isBranch bool
+	}{
+		{"b to itself", []byte{0xfe, 0xe7}, 0x10000100, "b 0x10000100", 0x10000100, true},
+		{"beq backwards", []byte{0xfc, 0xd0}, 0x10000100, "beq 0x100000fc", 0x100000fc, true},
+		{"cbz", []byte{0x00, 0xb1}, 0x10000100, "cbz r0, 0x10000104", 0x10000104, true},
+		{"cbnz", []byte{0x00, 0xbb}, 0x10000100, "cbnz r0, 0x10000144", 0x10000144, true},
+		{"bl forwards", []byte{0x00, 0xf0, 0x7e, 0xf8}, 0x10000100, "bl 0x10000200", 0x10000200, true},
+		{"bl backwards", []byte{0xff, 0xf7, 0x7e, 0xff}, 0x10000100, "bl 0x10000000", 0x10000000, true},
+		{"b.w to itself", []byte{0xff, 0xf7, 0xfe, 0xbf}, 0x10000102, "b.w 0x10000102", 0x10000102, true},
+		{"beq.w forwards", []byte{0x13, 0xf0, 0x00, 0x80}, 0x10000100, "beq.w 0x10013104", 0x10013104, true},
+		// Literal loads and adr use the PC rounded down to a word
+		{"ldr literal", []byte{0x02, 0x48}, 0x10000100, "ldr r0, [pc, #8]", 0x1000010c, false},
+		{"ldr literal, odd halfword", []byte{0x02, 0x48}, 0x10000102, "ldr r0, [pc, #8]", 0x1000010c, false},
+		{"adr, odd halfword", []byte{0x01, 0xa0}, 0x10000102, "adr r0, #4", 0x10000108, false},
+		{"ldr.w literal", []byte{0xdf, 0xf8, 0x08, 0x10}, 0x10000102, "ldr.w r1, [pc, #8]", 0x1000010c, false},
+		{"ldr.w literal backwards", []byte{0x5f, 0xf8, 0x0c, 0x00}, 0x10000102, "ldr.w r0, [pc, #-12]", 0x100000f8, false},
+	}
+
+	for _, tt := range tests {
+		insts := DecodeThumb(tt.code, tt.addr)
+		if len(insts) != 1 {
+			t.Errorf("%s: decoded %d instructions, want 1", tt.name, len(insts))
+			continue
+		}
+		inst := insts[0]
+		if got := inst.Mnemonic + " " + inst.Operands; got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+		if !inst.HasTarget || inst.Target != tt.target || inst.IsBranch != tt.isBranch {
+			t.Errorf("%s: target 0x%08x (has %v, branch %v), want 0x%08x (branch %v)",
+				tt.name, inst.Target, inst.HasTarget, inst.IsBranch, tt.target, tt.isBranch)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Address expressions are written like C:
+//
+//	0x20001000          Hex, or decimal (4096) or binary (0b1000)
+//	main+0x40           Names are looked up in Env, e.g. symbols and landmarks
+//	sram_end-0x100      Region names with _start, _end and _size
+//	*(0x20001000)+8     * reads a 32-bit little-endian word from the device
+//	(buf+4*i) & ~3      + - * / % << >> & | ^ ~ and parentheses
+//
+// Operators have C precedence, so *p+8 is (*p)+8.
+
+// ErrUnknownName is returned for names Env.Lookup doesn't know
+var ErrUnknownName = errors.New("unknown name")
+
+// ErrNoDevice is returned by Eval for dereferences when Env.Read is nil
+var ErrNoDevice = errors.New("dereferencing needs a device")
+
+// Env gives names and dereferences their meaning
+type Env struct {
+	Lookup func(name string) (uint32, bool)
+	Read   func(addr uint32) (uint32, error) // Reads a word for *; nil if there is no device
+}
+
+// Eval evaluates an address expression. A name that Lookup knows is accepted
+// as the whole text even if it isn't a valid identifier, e.g. a bookmark with
+// spaces in its name.
+func Eval(text string, env Env) (uint32, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return 0, fmt.Errorf("empty expression")
+	}
+	if env.Lookup != nil {
+		if val, ok := env.Lookup(text); ok {
+			return val, nil
+		}
+	}
+
+	tokens, err := tokenize(text)
+	if err != nil {
+		return 0, err
+	}
+	p := &parser{tokens: tokens, env: env}
+	val, err := p.parseBinary(0)
+	if err != nil {
+		return 0, err
+	}
+	if tok := p.peek(); tok != "" {
+		return 0, fmt.Errorf("unexpected %q", tok)
+	}
+	if val < 0 || val > math.MaxUint32 {
+		return 0, fmt.Errorf("result %d is not a 32-bit address", val)
+	}
+	return uint32(val), nil
+}
+
+var operators = []string{"<<", ">>", "+", "-", "*", "/", "%", "&", "|", "^", "~", "(", ")"}
+
+func isNameRune(c rune) bool {
+	return c == '_' || c == '.' || c == '$' || unicode.IsLetter(c) || unicode.IsDigit(c)
+}
+
+func tokenize(text string) ([]string, error) {
+	var tokens []string
+	runes := []rune(text)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case unicode.IsSpace(c):
+			i++
+		case isNameRune(c):
+			start := i
+			for i < len(runes) && isNameRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+		default:
+			matched := false
+			for _, op := range operators {
+				if strings.HasPrefix(string(runes[i:]), op) {
+					tokens = append(tokens, op)
+					i += len([]rune(op))
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return nil, fmt.Errorf("unexpected %q", c)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// parser evaluates the tokens as it reads them
+type parser struct {
+	tokens []string
+	pos    int
+	env    Env
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+// Binary operators from loosest to tightest, as in C
+var precedence = [][]string{
+	{"|"},
+	{"^"},
+	{"&"},
+	{"<<", ">>"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *parser) parseBinary(level int) (int64, error) {
+	if level == len(precedence) {
+		return p.parseUnary()
+	}
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return 0, err
+	}
+	for {
+		op := p.peek()
+		if !slices.Contains(precedence[level], op) {
+			return left, nil
+		}
+		p.next()
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return 0, err
+		}
+		if left, err = apply(op, left, right); err != nil {
+			return 0, err
+		}
+	}
+}
+
+func (p *parser) parseUnary() (int64, error) {
+	switch p.peek() {
+	case "-", "~", "*", "+":
+		op := p.next()
+		val, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case "-":
+			return -val, nil
+		case "~":
+			return int64(^uint32(val)), nil
+		case "*":
+			return p.deref(val)
+		}
+		return val, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (int64, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return 0, fmt.Errorf("expression ends too early")
+	case tok == "(":
+		val, err := p.parseBinary(0)
+		if err != nil {
+			return 0, err
+		}
+		if got := p.next(); got != ")" {
+			return 0, fmt.Errorf("missing )")
+		}
+		return val, nil
+	case unicode.IsDigit(rune(tok[0])):
+		return parseNumber(tok)
+	case isNameRune(rune(tok[0])):
+		if p.env.Lookup != nil {
+			if val, ok := p.env.Lookup(tok); ok {
+				return int64(val), nil
+			}
+		}
+		return 0, fmt.Errorf("%w %q", ErrUnknownName, tok)
+	}
+	return 0, fmt.Errorf("unexpected %q", tok)
+}
+
+func (p *parser) deref(addr int64) (int64, error) {
+	if addr < 0 || addr > math.MaxUint32 {
+		return 0, fmt.Errorf("cannot dereference %d", addr)
+	}
+	if p.env.Read == nil {
+		return 0, ErrNoDevice
+	}
+	val, err := p.env.Read(uint32(addr))
+	if err != nil {
+		return 0, fmt.Errorf("reading 0x%08x: %w", addr, err)
+	}
+	return int64(val), nil
+}
+
+// parseNumber reads hex (0x), binary (0b) or decimal. A leading 0 is not
+// octal, so 010 is ten.
+func parseNumber(tok string) (int64, error) {
+	lower := strings.ToLower(tok)
+	var val uint64
+	var err error
+	switch {
+	case strings.HasPrefix(lower, "0x"):
+		val, err = strconv.ParseUint(lower[2:], 16, 32)
+	case strings.HasPrefix(lower, "0b"):
+		val, err = strconv.ParseUint(lower[2:], 2, 32)
+	default:
+		val, err = strconv.ParseUint(lower, 10, 32)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", tok)
+	}
+	return int64(val), nil
+}
+
+// apply works on int64 so intermediate values can go negative, as in
+// -0x100+sram_end, but reports any step that no longer fits in 32 bits rather
+// than letting it wrap (0xFFFFFFFF*0xFFFFFFFF, 1<<31<<1).
+func apply(op string, a, b int64) (int64, error) {
+	var val int64
+	switch op {
+	case "+":
+		val = a + b
+	case "-":
+		val = a - b
+	case "*":
+		// Checked before multiplying, as the product of two 32-bit values can overflow int64
+		if b != 0 && abs(a) > math.MaxUint32/abs(b) {
+			return 0, fmt.Errorf("%d %s %d overflows 32 bits", a, op, b)
+		}
+		val = a * b
+	case "/", "%":
+		if b == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		if op == "/" {
+			val = a / b
+		} else {
+			val = a % b
+		}
+	case "<<", ">>":
+		if b < 0 || b > 31 {
+			return 0, fmt.Errorf("shift by %d", b)
+		}
+		if op == "<<" {
+			val = a << b
+		} else {
+			val = a >> b
+		}
+	case "&":
+		val = a & b
+	case "|":
+		val = a | b
+	case "^":
+		val = a ^ b
+	default:
+		return 0, fmt.Errorf("unknown operator %q", op)
+	}
+	if val < -math.MaxUint32 || val > math.MaxUint32 {
+		return 0, fmt.Errorf("%d %s %d overflows 32 bits", a, op, b)
+	}
+	return val, nil
+}
+
+func abs(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,138 @@
+package expr
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var testEnv = Env{
+	Lookup: func(name string) (uint32, bool) {
+		names := map[string]uint32{
+			"p":         0x20001000,
+			"main":      0x10000234,
+			"sram_end":  0x20082000,
+			"my buffer": 0x20003000,
+		}
+		val, ok := names[name]
+		return val, ok
+	},
+	Read: func(addr uint32) (uint32, error) {
+		switch addr {
+		case 0x20001000:
+			return 0x20002000, nil
+		case 0x20002000:
+			return 0x00000042, nil
+		}
+		return 0, fmt.Errorf("bus fault")
+	},
+}
+
+func TestEval(t *testing.T) {
+	tests := []struct {
+		text string
+		want uint32
+	}{
+		// Literals
+		{"0x20001000", 0x20001000},
+		{"0X2000ABCD", 0x2000abcd},
+		{"4096", 4096},
+		{"010", 10},
+		{"0b1000", 8},
+		{"0xFFFFFFFF", 0xffffffff},
+
+		// Names
+		{"main", 0x10000234},
+		{"main+0x40", 0x10000274},
+		{"sram_end-0x100", 0x20081f00},
+		{"my buffer", 0x20003000},
+
+		// Precedence
+		{"*p+8", 0x20002008},
+		{"*(p)+8", 0x20002008},
+		{"*(*p)", 0x42},
+		{"**p", 0x42},
+		{"2+3*4", 14},
+		{"(2+3)*4", 20},
+		{"1<<4+1", 32},
+		{"0x10|0x01&0x03", 0x11},
+		{"0xF0^0xFF&0x0F", 0xFF},
+		{"100-10-10", 80},
+		{"100/10/5", 2},
+		{"17%5", 2},
+
+		// Unary operators
+		{"~0", 0xffffffff},
+		{"~0xF", 0xfffffff0},
+		{"(p+7) & ~3", 0x20001004},
+		{"-0x100+sram_end", 0x20081f00},
+		{"-(-4)", 4},
+		{"+8", 8},
+
+		// Intermediate values may be negative if the result isn't
+		{"0x100-0x200+0x300", 0x200},
+		{"1<<31", 0x80000000},
+		{"0xFFFF*0x10001", 0xffffffff},
+	}
+
+	for _, tt := range tests {
+		got, err := Eval(tt.text, testEnv)
+		if err != nil {
+			t.Errorf("Eval(%q): %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Eval(%q) = 0x%08x, want 0x%08x", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	tests := []struct {
+		text    string
+		wantErr string
+	}{
+		{"", "empty expression"},
+		{"0x", "invalid number"},
+		{"0x100000000", "invalid number"},
+		{"0b102", "invalid number"},
+		{"12ab", "invalid number"},
+		{"nope", "unknown name"},
+		{"main+", "ends too early"},
+		{"(main+4", "missing )"},
+		{"main)", "unexpected \")\""},
+		{"main # 4", "unexpected '#'"},
+		{"1/0", "division by zero"},
+		{"1%0", "division by zero"},
+		{"1<<32", "shift by 32"},
+		{"1>>-1", "shift by -1"},
+		{"0xFFFFFFFF*0xFFFFFFFF", "overflows 32 bits"},
+		{"1<<31<<1", "overflows 32 bits"},
+		{"0xFFFFFFFF+1", "overflows 32 bits"},
+		{"-0xFFFFFFFF-1", "overflows 32 bits"},
+		{"0-1", "not a 32-bit address"},
+		{"*0x30000000", "bus fault"},
+		{"*(0-4)", "cannot dereference"},
+	}
+
+	for _, tt := range tests {
+		_, err := Eval(tt.text, testEnv)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Eval(%q): err = %v, want %q", tt.text, err, tt.wantErr)
+		}
+	}
+}
+
+func TestEvalWithoutDevice(t *testing.T) {
+	env := Env{Lookup: testEnv.Lookup}
+	if _, err := Eval("*p+8", env); !errors.Is(err, ErrNoDevice) {
+		t.Errorf("Eval(*p+8) without a device: err = %v, want ErrNoDevice", err)
+	}
+	if got, err := Eval("p+8", env); err != nil || got != 0x20001008 {
+		t.Errorf("Eval(p+8) without a device = 0x%08x, %v", got, err)
+	}
+	if _, err := Eval("nope", env); !errors.Is(err, ErrUnknownName) {
+		t.Errorf("Eval(nope): err = %v, want ErrUnknownName", err)
+	}
+}
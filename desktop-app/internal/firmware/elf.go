@@ -0,0 +1,42 @@
+package firmware
+
+import (
+	"github.com/MironCo/picopeeker/internal/format"
+	"debug/elf"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// ParseELFSegments returns the loadable segments of an ELF file at their load
+// (physical) addresses, which is where they live in flash. Initialized data is
+// included at its flash copy, not at its run address in SRAM.
+func ParseELFSegments(r io.ReaderAt) ([]format.MemoryBlock, error) {
+	file, err := elf.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("not an ELF file: %w", err)
+	}
+	defer file.Close()
+
+	var blocks []format.MemoryBlock
+	for _, prog := range file.Progs {
+		if prog.Type != elf.PT_LOAD || prog.Filesz == 0 {
+			continue
+		}
+
+		data := make([]byte, prog.Filesz)
+		if _, err := prog.ReadAt(data, 0); err != nil {
+			return nil, fmt.Errorf("failed to read segment at 0x%08x: %w", prog.Paddr, err)
+		}
+		blocks = append(blocks, format.MemoryBlock{Address: uint32(prog.Paddr), Data: data})
+	}
+
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("ELF file has no loadable segments")
+	}
+
+	sort.Slice(blocks, func(i, j int) bool {
+		return blocks[i].Address < blocks[j].Address
+	})
+	return blocks, nil
+}
@@ -0,0 +1,182 @@
+package firmware
+
+import (
+	"debug/elf"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Symbol is a function or data object from an ELF symbol table
+type Symbol struct {
+	Name    string
+	Address uint32
+	Size    uint32
+	IsFunc  bool
+}
+
+// Section is an ELF section that occupies memory at run time, like .bss
+type Section struct {
+	Name    string
+	Address uint32
+	Size    uint32
+}
+
+// SymbolTable maps addresses to the symbols of a loaded ELF file
+type SymbolTable struct {
+	symbols  []Symbol // Sorted by address
+	byName   map[string]Symbol
+	sections []Section // Sorted by address
+}
+
+// LoadSymbols reads the function and object symbols of an ELF file. The Thumb
+// bit is cleared from function addresses so they match the code in memory.
+// Linker symbols like __bss_start__ can be found by name but don't label
+// addresses.
+func LoadSymbols(r io.ReaderAt) (*SymbolTable, error) {
+	file, err := elf.NewFile(r)
+	if err != nil {
+		return nil, fmt.Errorf("not an ELF file: %w", err)
+	}
+	defer file.Close()
+
+	elfSymbols, err := file.Symbols()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read symbols: %w", err)
+	}
+
+	table := &SymbolTable{byName: make(map[string]Symbol)}
+	var linkerSymbols []Symbol
+	for _, sym := range elfSymbols {
+		if sym.Name == "" || sym.Section == elf.SHN_UNDEF {
+			continue
+		}
+		symType := elf.ST_TYPE(sym.Info)
+		if symType == elf.STT_NOTYPE && sym.Name[0] != '$' { // $t/$d are ARM mapping symbols
+			linkerSymbols = append(linkerSymbols, Symbol{Name: sym.Name, Address: uint32(sym.Value)})
+			continue
+		}
+		if symType != elf.STT_FUNC && symType != elf.STT_OBJECT {
+			continue
+		}
+
+		symbol := Symbol{
+			Name:    sym.Name,
+			Address: uint32(sym.Value),
+			Size:    uint32(sym.Size),
+			IsFunc:  symType == elf.STT_FUNC,
+		}
+		if symbol.IsFunc && file.Machine == elf.EM_ARM {
+			symbol.Address &^= 1
+		}
+
+		table.symbols = append(table.symbols, symbol)
+		if _, exists := table.byName[symbol.Name]; !exists {
+			table.byName[symbol.Name] = symbol
+		}
+	}
+
+	if len(table.symbols) == 0 {
+		return nil, fmt.Errorf("ELF file has no symbols (was it stripped?)")
+	}
+	for _, symbol := range linkerSymbols {
+		if _, exists := table.byName[symbol.Name]; !exists {
+			table.byName[symbol.Name] = symbol
+		}
+	}
+
+	for _, sec := range file.Sections {
+		if sec.Flags&elf.SHF_ALLOC == 0 || sec.Size == 0 {
+			continue
+		}
+		table.sections = append(table.sections, Section{Name: sec.Name, Address: uint32(sec.Addr), Size: uint32(sec.Size)})
+	}
+	sort.SliceStable(table.sections, func(i, j int) bool {
+		return table.sections[i].Address < table.sections[j].Address
+	})
+
+	// Functions sort before objects at the same address so labels name the code
+	sort.SliceStable(table.symbols, func(i, j int) bool {
+		a, b := table.symbols[i], table.symbols[j]
+		if a.Address != b.Address {
+			return a.Address < b.Address
+		}
+		return a.IsFunc && !b.IsFunc
+	})
+	return table, nil
+}
+
+// Len returns the number of symbols
+func (t *SymbolTable) Len() int {
+	if t == nil {
+		return 0
+	}
+	return len(t.symbols)
+}
+
+// Lookup returns the symbol containing the address. Symbols without a size
+// only match their exact address.
+func (t *SymbolTable) Lookup(addr uint32) (Symbol, bool) {
+	if t == nil {
+		return Symbol{}, false
+	}
+
+	// Last symbol starting at or before addr
+	i := sort.Search(len(t.symbols), func(i int) bool {
+		return t.symbols[i].Address > addr
+	})
+	for i--; i >= 0; i-- {
+		sym := t.symbols[i]
+		if sym.Address == addr || addr-sym.Address < sym.Size {
+			// Prefer the first symbol at this address (see sort order)
+			for i > 0 && t.symbols[i-1].Address == sym.Address {
+				i--
+			}
+			return t.symbols[i], true
+		}
+		// Sizes of earlier symbols may still cover addr, but only near the start
+		if addr-sym.Address > 0x10000 {
+			break
+		}
+	}
+	return Symbol{}, false
+}
+
+// Name returns "symbol" or "symbol+0x10" for the address, or "" if no symbol covers it
+func (t *SymbolTable) Name(addr uint32) string {
+	sym, ok := t.Lookup(addr)
+	if !ok {
+		return ""
+	}
+	if addr == sym.Address {
+		return sym.Name
+	}
+	return fmt.Sprintf("%s+0x%x", sym.Name, addr-sym.Address)
+}
+
+// Find returns the symbol with the given name
+func (t *SymbolTable) Find(name string) (Symbol, bool) {
+	if t == nil {
+		return Symbol{}, false
+	}
+	sym, ok := t.byName[name]
+	return sym, ok
+}
+
+// Sections returns the sections that occupy memory, sorted by address
+func (t *SymbolTable) Sections() []Section {
+	if t == nil {
+		return nil
+	}
+	return t.sections
+}
+
+// FindSection returns the section with the given name, e.g. ".bss"
+func (t *SymbolTable) FindSection(name string) (Section, bool) {
+	for _, sec := range t.Sections() {
+		if sec.Name == name {
+			return sec, true
+		}
+	}
+	return Section{}, false
+}
@@ -0,0 +1,72 @@
+package firmware
+
+import (
+	"github.com/MironCo/picopeeker/internal/format"
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// UF2 block layout, see https://github.com/microsoft/uf2
+const (
+	uf2BlockSize    = 512
+	uf2MagicStart0  = 0x0A324655
+	uf2MagicStart1  = 0x9E5D5157
+	uf2MagicEnd     = 0x0AB16F30
+	uf2FlagNotMain  = 0x00000001 // Block is not for main flash
+	uf2MaxPayload   = 476
+	uf2PayloadStart = 32
+)
+
+// ParseUF2 reads a UF2 file and returns its payload as contiguous blocks,
+// in the order they appear in the file
+func ParseUF2(r io.Reader) ([]format.MemoryBlock, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 || len(data)%uf2BlockSize != 0 {
+		return nil, fmt.Errorf("not a UF2 file: size %d is not a multiple of %d", len(data), uf2BlockSize)
+	}
+
+	var blocks []format.MemoryBlock
+	for offset := 0; offset < len(data); offset += uf2BlockSize {
+		block := data[offset : offset+uf2BlockSize]
+
+		if binary.LittleEndian.Uint32(block[0:]) != uf2MagicStart0 ||
+			binary.LittleEndian.Uint32(block[4:]) != uf2MagicStart1 ||
+			binary.LittleEndian.Uint32(block[uf2BlockSize-4:]) != uf2MagicEnd {
+			return nil, fmt.Errorf("not a UF2 file: bad magic in block at offset %d", offset)
+		}
+
+		flags := binary.LittleEndian.Uint32(block[8:])
+		targetAddr := binary.LittleEndian.Uint32(block[12:])
+		payloadSize := binary.LittleEndian.Uint32(block[16:])
+		if flags&uf2FlagNotMain != 0 {
+			continue
+		}
+		if payloadSize > uf2MaxPayload {
+			return nil, fmt.Errorf("invalid UF2 block at offset %d: payload size %d", offset, payloadSize)
+		}
+
+		payload := block[uf2PayloadStart : uf2PayloadStart+payloadSize]
+		blocks = appendBlock(blocks, targetAddr, payload)
+	}
+
+	if len(blocks) == 0 {
+		return nil, fmt.Errorf("UF2 file has no main flash blocks")
+	}
+	return blocks, nil
+}
+
+// appendBlock adds data at addr, merging it into the previous block if contiguous
+func appendBlock(blocks []format.MemoryBlock, addr uint32, data []byte) []format.MemoryBlock {
+	if n := len(blocks); n > 0 {
+		last := &blocks[n-1]
+		if last.Address+uint32(len(last.Data)) == addr {
+			last.Data = append(last.Data, data...)
+			return blocks
+		}
+	}
+	return append(blocks, format.MemoryBlock{Address: addr, Data: append([]byte(nil), data...)})
+}
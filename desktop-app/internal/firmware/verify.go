@@ -0,0 +1,115 @@
+package firmware
+
+import (
+	"github.com/MironCo/picopeeker/internal/format"
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// LoadImage parses a firmware image, picking UF2 or ELF from the file name
+// (or the ELF magic if the extension is unknown)
+func LoadImage(name string, data []byte) ([]format.MemoryBlock, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".uf2":
+		return ParseUF2(bytes.NewReader(data))
+	case ".elf", ".axf":
+		return ParseELFSegments(bytes.NewReader(data))
+	}
+
+	if bytes.HasPrefix(data, []byte("\x7fELF")) {
+		return ParseELFSegments(bytes.NewReader(data))
+	}
+	return ParseUF2(bytes.NewReader(data))
+}
+
+// FilterRange keeps only the parts of the blocks that fall inside [start, end)
+func FilterRange(blocks []format.MemoryBlock, start, end uint32) []format.MemoryBlock {
+	var result []format.MemoryBlock
+	for _, block := range blocks {
+		blockStart := uint64(block.Address)
+		blockEnd := blockStart + uint64(len(block.Data))
+
+		lo := max(blockStart, uint64(start))
+		hi := min(blockEnd, uint64(end))
+		if lo >= hi {
+			continue
+		}
+		result = append(result, format.MemoryBlock{
+			Address: uint32(lo),
+			Data:    block.Data[lo-blockStart : hi-blockStart],
+		})
+	}
+	return result
+}
+
+// ChunkResult is the outcome of comparing one chunk of the image with the device
+type ChunkResult struct {
+	Address  uint32
+	Expected []byte // From the image
+	Actual   []byte // Read from the device, nil if the read failed
+	Diffs    int    // Number of differing bytes
+	Err      error
+}
+
+// Match reports whether the chunk was read and is identical to the image
+func (c ChunkResult) Match() bool {
+	return c.Err == nil && c.Diffs == 0
+}
+
+// Summary returns a one-line description of the chunk result
+func (c ChunkResult) Summary() string {
+	switch {
+	case c.Err != nil:
+		return fmt.Sprintf("0x%08x  %5d bytes  ERROR: %v", c.Address, len(c.Expected), c.Err)
+	case c.Diffs == 0:
+		return fmt.Sprintf("0x%08x  %5d bytes  OK", c.Address, len(c.Expected))
+	default:
+		return fmt.Sprintf("0x%08x  %5d bytes  MISMATCH (%d bytes differ)", c.Address, len(c.Expected), c.Diffs)
+	}
+}
+
+// Verify reads each block back from the device in chunks of at most chunkSize
+// bytes and compares it with the image. progress (may be nil) is called after
+// each chunk with the number of bytes checked so far.
+func Verify(blocks []format.MemoryBlock, chunkSize int, read func(address uint32, length int) ([]byte, error), progress func(done, total int)) []ChunkResult {
+	total := 0
+	for _, block := range blocks {
+		total += len(block.Data)
+	}
+
+	var results []ChunkResult
+	done := 0
+	for _, block := range blocks {
+		for offset := 0; offset < len(block.Data); offset += chunkSize {
+			end := min(offset+chunkSize, len(block.Data))
+			result := ChunkResult{
+				Address:  block.Address + uint32(offset),
+				Expected: block.Data[offset:end],
+			}
+
+			actual, err := read(result.Address, len(result.Expected))
+			if err == nil && len(actual) != len(result.Expected) {
+				err = fmt.Errorf("short read: got %d of %d bytes", len(actual), len(result.Expected))
+			}
+			if err != nil {
+				result.Err = err
+			} else {
+				result.Actual = actual
+				for i := range actual {
+					if actual[i] != result.Expected[i] {
+						result.Diffs++
+					}
+				}
+			}
+
+			results = append(results, result)
+			done += len(result.Expected)
+			if progress != nil {
+				progress(done, total)
+			}
+		}
+	}
+	return results
+}
@@ -0,0 +1,282 @@
+package format
+
+import (
+	"github.com/MironCo/picopeeker/internal/disasm"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Formatter renders raw bytes read from startAddr for one "Display as" mode
+type Formatter func(data []byte, startAddr uint32) string
+
+type displayFormat struct {
+	name   string
+	format Formatter
+}
+
+// displayFormats holds the registered formats in the order shown in the UI
+var displayFormats []displayFormat
+
+// Display modes handled specially by the UI (passthrough and rich disassembly)
+const (
+	DisplayBytes = "Bytes (Hex)"
+	DisplayThumb = "Disassembly (Thumb)"
+	DisplayRISCV = "Disassembly (RISC-V)"
+)
+
+// RegisterFormatter adds a display mode. Registering an existing name replaces its formatter.
+func RegisterFormatter(name string, format Formatter) {
+	for i := range displayFormats {
+		if displayFormats[i].name == name {
+			displayFormats[i].format = format
+			return
+		}
+	}
+	displayFormats = append(displayFormats, displayFormat{name: name, format: format})
+}
+
+// DisplayFormats returns the names of all display modes, in registration order
+func DisplayFormats() []string {
+	names := make([]string, len(displayFormats))
+	for i, f := range displayFormats {
+		names[i] = f.name
+	}
+	return names
+}
+
+// LookupFormatter returns the formatter for a display mode
+func LookupFormatter(name string) (Formatter, bool) {
+	for _, f := range displayFormats {
+		if f.name == name {
+			return f.format, true
+		}
+	}
+	return nil, false
+}
+
+func init() {
+	RegisterFormatter(DisplayBytes, nil) // The firmware's own hex dump is shown as is
+	RegisterFormatter("Binary", FormatAsBinary)
+	RegisterFormatter("8-bit Integers", FormatAs8BitInts)
+	RegisterFormatter("16-bit Words", FormatAs16BitWords)
+	RegisterFormatter("16-bit Words (Big-Endian)", wordFormatter(2, binary.BigEndian))
+	RegisterFormatter("32-bit Words", FormatAs32BitWords)
+	RegisterFormatter("32-bit Words (Big-Endian)", wordFormatter(4, binary.BigEndian))
+	RegisterFormatter("64-bit Words", wordFormatter(8, binary.LittleEndian))
+	RegisterFormatter("64-bit Words (Big-Endian)", wordFormatter(8, binary.BigEndian))
+	RegisterFormatter("Float (32-bit)", FormatAsFloats)
+	RegisterFormatter("Float (32-bit, Big-Endian)", floatFormatter(4, binary.BigEndian))
+	RegisterFormatter("Double (64-bit)", floatFormatter(8, binary.LittleEndian))
+	RegisterFormatter("Double (64-bit, Big-Endian)", floatFormatter(8, binary.BigEndian))
+	RegisterFormatter("Fixed Q15 (16-bit)", fixedFormatter(2, 15))
+	RegisterFormatter("Fixed Q16.16 (32-bit)", fixedFormatter(4, 16))
+	RegisterFormatter("Strings", func(data []byte, startAddr uint32) string {
+		return FormatStrings(ExtractStrings(data, startAddr, DefaultMinStringLength))
+	})
+	RegisterFormatter(DisplayThumb, func(data []byte, startAddr uint32) string {
+		return disasm.FormatListing("Disassembly (Thumb)", disasm.DecodeThumb(data, startAddr), nil)
+	})
+	RegisterFormatter(DisplayRISCV, func(data []byte, startAddr uint32) string {
+		return disasm.FormatListing("Disassembly (RISC-V)", disasm.DecodeRISCV(data, startAddr), nil)
+	})
+}
+
+// column is one value column of a table view
+type column struct {
+	header string
+	value  func(word []byte) string
+}
+
+// formatTable renders data as rows of size-byte words, one column per value.
+// Column widths fit the widest value so any combination of columns lines up.
+func formatTable(title string, data []byte, startAddr uint32, size int, columns []column) string {
+	rows := [][]string{}
+	for i := 0; i+size <= len(data); i += size {
+		word := data[i : i+size]
+		hexBytes := make([]string, size)
+		for j, b := range word {
+			hexBytes[j] = fmt.Sprintf("%02x", b)
+		}
+
+		row := []string{fmt.Sprintf("%08x:", startAddr+uint32(i)), strings.Join(hexBytes, " ")}
+		for _, col := range columns {
+			row = append(row, col.value(word))
+		}
+		rows = append(rows, row)
+	}
+
+	headers := []string{"Address:", "Hex Bytes"}
+	for _, col := range columns {
+		headers = append(headers, col.header)
+	}
+	widths := make([]int, len(headers))
+	for i, header := range headers {
+		widths[i] = len(header)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+
+	var sb strings.Builder
+	writeRow := func(cells []string) {
+		var line strings.Builder
+		for i, cell := range cells {
+			switch {
+			case i == len(cells)-1:
+				line.WriteString(cell)
+			case i == 0:
+				// "00000000: " like the firmware's hex dump
+				line.WriteString(fmt.Sprintf("%-*s ", widths[i], cell))
+			default:
+				line.WriteString(fmt.Sprintf("%-*s  ", widths[i], cell))
+			}
+		}
+		sb.WriteString(strings.TrimRight(line.String(), " ") + "\n")
+	}
+
+	sb.WriteString(fmt.Sprintf("=== %s ===\n\n", title))
+	writeRow(headers)
+	dashes := make([]string, len(headers))
+	for i, width := range widths {
+		dashes[i] = strings.Repeat("-", width)
+	}
+	dashes[0] = strings.Repeat("-", 8)
+	writeRow(dashes)
+	for _, row := range rows {
+		writeRow(row)
+	}
+
+	// Bytes left over after the last whole word
+	if remainder := len(data) % size; remainder > 0 {
+		i := len(data) - remainder
+		sb.WriteString(fmt.Sprintf("%08x: ", startAddr+uint32(i)))
+		for _, b := range data[i:] {
+			sb.WriteString(fmt.Sprintf("%02x ", b))
+		}
+		sb.WriteString("(partial)\n")
+	}
+
+	sb.WriteString("\n===END===\n")
+	return sb.String()
+}
+
+func byteOrderName(order binary.ByteOrder) string {
+	if order == binary.BigEndian {
+		return "Big-Endian"
+	}
+	return "Little-Endian"
+}
+
+// readWord reads a 2, 4 or 8 byte unsigned value
+func readWord(word []byte, order binary.ByteOrder) uint64 {
+	switch len(word) {
+	case 2:
+		return uint64(order.Uint16(word))
+	case 4:
+		return uint64(order.Uint32(word))
+	default:
+		return order.Uint64(word)
+	}
+}
+
+// signedWord sign-extends a value read by readWord
+func signedWord(val uint64, size int) int64 {
+	shift := 64 - 8*size
+	return int64(val<<shift) >> shift
+}
+
+// wordFormatter shows integers of the given size with hex, signed and unsigned values
+func wordFormatter(size int, order binary.ByteOrder) Formatter {
+	title := fmt.Sprintf("%d-bit Word View (%s)", size*8, byteOrderName(order))
+	return func(data []byte, startAddr uint32) string {
+		return formatTable(title, data, startAddr, size, []column{
+			{"Value", func(word []byte) string {
+				return fmt.Sprintf("0x%0*x", size*2, readWord(word, order))
+			}},
+			{"Decimal (signed)", func(word []byte) string {
+				return fmt.Sprintf("%d", signedWord(readWord(word, order), size))
+			}},
+			{"Decimal (unsigned)", func(word []byte) string {
+				return fmt.Sprintf("%d", readWord(word, order))
+			}},
+		})
+	}
+}
+
+// floatFormatter shows IEEE 754 single (size 4) or double (size 8) values
+func floatFormatter(size int, order binary.ByteOrder) Formatter {
+	name := "Float View (32-bit"
+	if size == 8 {
+		name = "Double View (64-bit"
+	}
+	title := fmt.Sprintf("%s, %s)", name, byteOrderName(order))
+	return func(data []byte, startAddr uint32) string {
+		return formatTable(title, data, startAddr, size, []column{
+			{"Value", func(word []byte) string {
+				if size == 4 {
+					return fmt.Sprintf("%g", math.Float32frombits(order.Uint32(word)))
+				}
+				return fmt.Sprintf("%g", math.Float64frombits(order.Uint64(word)))
+			}},
+		})
+	}
+}
+
+// fixedFormatter shows signed little-endian fixed-point values with fracBits fractional bits
+func fixedFormatter(size int, fracBits uint) Formatter {
+	intBits := uint(size*8) - fracBits
+	title := fmt.Sprintf("Fixed-Point Q%d.%d View (Little-Endian)", intBits, fracBits)
+	if intBits == 1 {
+		title = fmt.Sprintf("Fixed-Point Q%d View (Little-Endian)", fracBits)
+	}
+	return func(data []byte, startAddr uint32) string {
+		return formatTable(title, data, startAddr, size, []column{
+			{"Raw", func(word []byte) string {
+				return fmt.Sprintf("%d", signedWord(readWord(word, binary.LittleEndian), size))
+			}},
+			{"Value", func(word []byte) string {
+				raw := signedWord(readWord(word, binary.LittleEndian), size)
+				return fmt.Sprintf("%.6f", float64(raw)/float64(uint64(1)<<fracBits))
+			}},
+		})
+	}
+}
+
+// FormatAs8BitInts shows each byte as unsigned, signed and as a character
+func FormatAs8BitInts(data []byte, startAddr uint32) string {
+	return formatTable("8-bit Integer View", data, startAddr, 1, []column{
+		{"Unsigned", func(word []byte) string { return fmt.Sprintf("%d", word[0]) }},
+		{"Signed", func(word []byte) string { return fmt.Sprintf("%d", int8(word[0])) }},
+		{"Char", func(word []byte) string {
+			if word[0] >= 0x20 && word[0] < 0x7F {
+				return fmt.Sprintf("'%c'", word[0])
+			}
+			return ""
+		}},
+	})
+}
+
+// FormatAsBinary shows the bits of each byte, 4 bytes per row
+func FormatAsBinary(data []byte, startAddr uint32) string {
+	var sb strings.Builder
+	sb.WriteString("=== Binary View ===\n\n")
+	sb.WriteString("Address:  Hex Bytes    Bits (MSB first)\n")
+	sb.WriteString("--------  -----------  -----------------------------------\n")
+
+	for i := 0; i < len(data); i += 4 {
+		end := min(i+4, len(data))
+		var hexBytes, bits []string
+		for _, b := range data[i:end] {
+			hexBytes = append(hexBytes, fmt.Sprintf("%02x", b))
+			bits = append(bits, fmt.Sprintf("%08b", b))
+		}
+		sb.WriteString(fmt.Sprintf("%08x: %-11s  %s\n", startAddr+uint32(i), strings.Join(hexBytes, " "), strings.Join(bits, " ")))
+	}
+
+	sb.WriteString("\n===END===\n")
+	return sb.String()
+}
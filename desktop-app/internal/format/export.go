@@ -0,0 +1,233 @@
+package format
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// MemoryBlock is a contiguous run of bytes read from the device
+type MemoryBlock struct {
+	Address uint32
+	Data    []byte
+}
+
+// ParseMemoryBlock extracts the start address and raw bytes from a hex dump
+func ParseMemoryBlock(dump string) MemoryBlock {
+	return MemoryBlock{
+		Address: ExtractStartAddress(dump),
+		Data:    ParseHexDump(dump),
+	}
+}
+
+// Export format names, in the order shown in the UI
+const (
+	ExportBinary   = "Raw Binary (.bin)"
+	ExportIntelHex = "Intel HEX (.hex)"
+	ExportSRecord  = "Motorola S-record (.srec)"
+	ExportCArray   = "C Array (.c)"
+)
+
+// ExportFormats lists the supported export formats
+var ExportFormats = []string{ExportBinary, ExportIntelHex, ExportSRecord, ExportCArray}
+
+// ExportExtension returns the default file extension for an export format
+func ExportExtension(exportFormat string) string {
+	switch exportFormat {
+	case ExportIntelHex:
+		return ".hex"
+	case ExportSRecord:
+		return ".srec"
+	case ExportCArray:
+		return ".c"
+	default:
+		return ".bin"
+	}
+}
+
+// ExportFormatForFile picks the export format from a file name's extension
+func ExportFormatForFile(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".hex", ".ihex", ".ihx":
+		return ExportIntelHex
+	case ".srec", ".s19", ".s28", ".s37", ".mot":
+		return ExportSRecord
+	case ".c", ".h":
+		return ExportCArray
+	default:
+		return ExportBinary
+	}
+}
+
+// ExportBlock writes the block in the given export format
+func ExportBlock(w io.Writer, block MemoryBlock, exportFormat string) error {
+	switch exportFormat {
+	case ExportBinary:
+		return WriteBinary(w, block)
+	case ExportIntelHex:
+		return WriteIntelHex(w, block)
+	case ExportSRecord:
+		return WriteSRecord(w, block)
+	case ExportCArray:
+		return WriteCArray(w, block, fmt.Sprintf("mem_%08x", block.Address))
+	default:
+		return fmt.Errorf("unknown export format %q", exportFormat)
+	}
+}
+
+// WriteBinary writes the raw bytes, without any address information
+func WriteBinary(w io.Writer, block MemoryBlock) error {
+	_, err := w.Write(block.Data)
+	return err
+}
+
+// WriteIntelHex writes the block as Intel HEX. Extended linear address records
+// (type 04) carry the upper 16 bits, so 0x10000000 and 0x20000000 load correctly.
+func WriteIntelHex(w io.Writer, block MemoryBlock) error {
+	const recordSize = 16
+	upper := -1
+
+	for i := 0; i < len(block.Data); {
+		addr := block.Address + uint32(i)
+
+		// New extended linear address record whenever the upper half changes
+		if int(addr>>16) != upper {
+			upper = int(addr >> 16)
+			if err := writeIntelHexRecord(w, 0, 0x04, []byte{byte(upper >> 8), byte(upper)}); err != nil {
+				return err
+			}
+		}
+
+		// Don't let a record cross a 64KB boundary
+		n := recordSize
+		if remaining := len(block.Data) - i; remaining < n {
+			n = remaining
+		}
+		if toBoundary := 0x10000 - int(addr&0xFFFF); toBoundary < n {
+			n = toBoundary
+		}
+
+		if err := writeIntelHexRecord(w, uint16(addr), 0x00, block.Data[i:i+n]); err != nil {
+			return err
+		}
+		i += n
+	}
+
+	// End of file record
+	return writeIntelHexRecord(w, 0, 0x01, nil)
+}
+
+func writeIntelHexRecord(w io.Writer, addr uint16, recordType byte, data []byte) error {
+	var sb strings.Builder
+	checksum := byte(len(data)) + byte(addr>>8) + byte(addr) + recordType
+
+	sb.WriteString(fmt.Sprintf(":%02X%04X%02X", len(data), addr, recordType))
+	for _, b := range data {
+		sb.WriteString(fmt.Sprintf("%02X", b))
+		checksum += b
+	}
+	sb.WriteString(fmt.Sprintf("%02X\n", byte(-checksum)))
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteSRecord writes the block as Motorola S-records with 32-bit addresses
+// (S0 header, S3 data, S5 or S6 count, S7 termination)
+func WriteSRecord(w io.Writer, block MemoryBlock) error {
+	const recordSize = 16
+
+	if err := writeSRecord(w, '0', 0, 2, []byte("picopeeker")); err != nil {
+		return err
+	}
+
+	count := 0
+	for i := 0; i < len(block.Data); i += recordSize {
+		end := i + recordSize
+		if end > len(block.Data) {
+			end = len(block.Data)
+		}
+		if err := writeSRecord(w, '3', block.Address+uint32(i), 4, block.Data[i:end]); err != nil {
+			return err
+		}
+		count++
+	}
+
+	// S5 holds a 16-bit count and S6 a 24-bit one. Even the whole 4GB address
+	// space is only 0x10000000 records, but anything past S6 is left uncounted.
+	switch {
+	case count <= 0xFFFF:
+		if err := writeSRecord(w, '5', uint32(count), 2, nil); err != nil {
+			return err
+		}
+	case count <= 0xFFFFFF:
+		if err := writeSRecord(w, '6', uint32(count), 3, nil); err != nil {
+			return err
+		}
+	}
+
+	return writeSRecord(w, '7', block.Address, 4, nil)
+}
+
+func writeSRecord(w io.Writer, recordType byte, addr uint32, addrLen int, data []byte) error {
+	var sb strings.Builder
+	byteCount := addrLen + len(data) + 1 // Address + data + checksum
+	sum := byte(byteCount)
+
+	sb.WriteString(fmt.Sprintf("S%c%02X", recordType, byteCount))
+	for i := addrLen - 1; i >= 0; i-- {
+		b := byte(addr >> (8 * i))
+		sb.WriteString(fmt.Sprintf("%02X", b))
+		sum += b
+	}
+	for _, b := range data {
+		sb.WriteString(fmt.Sprintf("%02X", b))
+		sum += b
+	}
+	sb.WriteString(fmt.Sprintf("%02X\n", ^sum))
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteCArray writes the block as C source for a const uint8_t array
+func WriteCArray(w io.Writer, block MemoryBlock, name string) error {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("// Read from 0x%08x, %d bytes\n", block.Address, len(block.Data)))
+	sb.WriteString("#include <stdint.h>\n\n")
+	sb.WriteString(fmt.Sprintf("const uint8_t %s[%d] = {\n", name, len(block.Data)))
+
+	for i := 0; i < len(block.Data); i += 16 {
+		sb.WriteString("    ")
+		for j := i; j < i+16 && j < len(block.Data); j++ {
+			sb.WriteString(fmt.Sprintf("0x%02x,", block.Data[j]))
+			if j+1 < i+16 && j+1 < len(block.Data) {
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString(fmt.Sprintf("  // 0x%08x\n", block.Address+uint32(i)))
+	}
+
+	sb.WriteString("};\n")
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// WriteSearchHits writes search hits as CSV, with the symbol covering each
+// hit when name is set
+func WriteSearchHits(w io.Writer, hits []uint32, name func(uint32) string) error {
+	if _, err := fmt.Fprintln(w, "address,symbol"); err != nil {
+		return err
+	}
+	for _, addr := range hits {
+		symbol := ""
+		if name != nil {
+			symbol = name(addr)
+		}
+		if _, err := fmt.Fprintf(w, "0x%08x,%s\n", addr, symbol); err != nil {
+			return err
+		}
+	}
+	return nil
+}
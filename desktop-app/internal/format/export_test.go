@@ -0,0 +1,48 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteSRecord(t *testing.T) {
+	var buf bytes.Buffer
+	block := MemoryBlock{Address: 0x20000000, Data: []byte{0xde, 0xad, 0xbe, 0xef}}
+	if err := WriteSRecord(&buf, block); err != nil {
+		t.Fatal(err)
+	}
+	want := "S00D00007069636F7065656B6572CB\n" +
+		"S30920000000DEADBEEF9E\n" +
+		"S5030001FB\n" +
+		"S70520000000DA\n"
+	if buf.String() != want {
+		t.Errorf("WriteSRecord =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteSRecordCount(t *testing.T) {
+	tests := []struct {
+		records int
+		want    string // Count record
+	}{
+		{0xFFFF, "S503FFFFFE"},
+		{0x10000, "S604010000FA"},
+		{0x12345, "S60401234592"},
+	}
+
+	for _, tt := range tests {
+		var buf bytes.Buffer
+		block := MemoryBlock{Address: 0x10000000, Data: make([]byte, tt.records*16)}
+		if err := WriteSRecord(&buf, block); err != nil {
+			t.Fatal(err)
+		}
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		if got := lines[len(lines)-2]; got != tt.want {
+			t.Errorf("%d records: count record %q, want %s", tt.records, got, tt.want)
+		}
+		if got := len(lines); got != tt.records+3 {
+			t.Errorf("%d records: %d lines, want %d", tt.records, got, tt.records+3)
+		}
+	}
+}
@@ -30,14 +30,10 @@ func ParseHexDump(dump string) []byte {
 	lines := strings.Split(dump, "\n")
 
 	for _, line := range lines {
-		// Look for lines with hex dump format: "00000000: 01 02 03 04 ..."
-		if !strings.Contains(line, ":") {
-			continue
-		}
-
-		// Split on colon
+		// Only lines with hex dump format: "00000000: 01 02 03 04 ...". Others,
+		// like "WARNING: Length clamped from 32 to 16 bytes", have numbers too.
 		parts := strings.SplitN(line, ":", 2)
-		if len(parts) != 2 {
+		if len(parts) != 2 || !isDumpAddress(parts[0]) {
 			continue
 		}
 
@@ -63,11 +59,21 @@ func ParseHexDump(dump string) []byte {
 	return result
 }
 
+// isDumpAddress reports whether text is the 8 hex digit address that starts a dump line
+func isDumpAddress(text string) bool {
+	if len(text) != 8 {
+		return false
+	}
+	_, err := strconv.ParseUint(text, 16, 32)
+	return err == nil
+}
+
 // FormatMemoryDump reformats the hex dump based on the selected display mode
 func FormatMemoryDump(rawDump string, displayMode string) string {
-	// If not in bytes mode, we need to parse and reformat
-	if displayMode == "Bytes (Hex)" {
-		return rawDump // Return original format
+	// Unknown modes and the bytes mode show the original dump
+	format, ok := LookupFormatter(displayMode)
+	if !ok || format == nil {
+		return rawDump
 	}
 
 	// Parse the hex dump to extract bytes
@@ -79,17 +85,7 @@ func FormatMemoryDump(rawDump string, displayMode string) string {
 	// Extract starting address from the dump
 	startAddr := ExtractStartAddress(rawDump)
 
-	// Format based on display mode
-	switch displayMode {
-	case "16-bit Words":
-		return FormatAs16BitWords(bytes, startAddr)
-	case "32-bit Words":
-		return FormatAs32BitWords(bytes, startAddr)
-	case "Float (32-bit)":
-		return FormatAsFloats(bytes, startAddr)
-	default:
-		return rawDump
-	}
+	return format(bytes, startAddr)
 }
 
 // extractStartAddress extracts the starting address from the hex dump
@@ -199,3 +195,51 @@ func FormatAsFloats(bytes []byte, startAddr uint32) string {
 	sb.WriteString("\n===END===\n")
 	return sb.String()
 }
+
+// FormatHexDiff shows the rows where expected and actual differ, with the
+// differing bytes marked underneath
+func FormatHexDiff(expected, actual []byte, startAddr uint32) string {
+	var sb strings.Builder
+	sb.WriteString("=== Hex Diff (image vs device) ===\n\n")
+	sb.WriteString("Address:  Source  00 01 02 03 04 05 06 07 08 09 0A 0B 0C 0D 0E 0F\n")
+	sb.WriteString("--------  ------  -----------------------------------------------\n")
+
+	rows := 0
+	for i := 0; i < len(expected) && i < len(actual); i += 16 {
+		end := i + 16
+		if end > len(expected) {
+			end = len(expected)
+		}
+		if end > len(actual) {
+			end = len(actual)
+		}
+
+		var exp, act, marks strings.Builder
+		differs := false
+		for j := i; j < end; j++ {
+			exp.WriteString(fmt.Sprintf("%02x ", expected[j]))
+			act.WriteString(fmt.Sprintf("%02x ", actual[j]))
+			if expected[j] != actual[j] {
+				marks.WriteString("^^ ")
+				differs = true
+			} else {
+				marks.WriteString("   ")
+			}
+		}
+		if !differs {
+			continue
+		}
+
+		sb.WriteString(fmt.Sprintf("%08x: image   %s\n", startAddr+uint32(i), exp.String()))
+		sb.WriteString(fmt.Sprintf("          device  %s\n", act.String()))
+		sb.WriteString(fmt.Sprintf("                  %s\n", strings.TrimRight(marks.String(), " ")))
+		rows++
+	}
+
+	if rows == 0 {
+		sb.WriteString("(no differences)\n")
+	}
+
+	sb.WriteString("\n===END===\n")
+	return sb.String()
+}
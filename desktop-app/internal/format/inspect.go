@@ -0,0 +1,139 @@
+package format
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// InspectorField is one interpretation of the inspected bytes
+type InspectorField struct {
+	Name  string
+	Value string
+}
+
+// MaxInspectText limits how much of a selection is shown as text
+const MaxInspectText = 64
+
+// Inspect decodes data as every supported type at once. Numbers start at the
+// first byte and may use bytes past a short selection, like the cursor of a hex
+// editor; the text fields only cover the first textLen bytes (at most
+// MaxInspectText). describePointer, if set, names the target of the 32-bit
+// pointer (e.g. a symbol).
+func Inspect(data []byte, textLen int, describePointer func(uint32) string) []InspectorField {
+	const missing = "-"
+	var fields []InspectorField
+	add := func(name, value string) {
+		fields = append(fields, InspectorField{name, value})
+	}
+
+	// Integers in both byte orders, where there are enough bytes
+	if len(data) >= 1 {
+		add("Binary", fmt.Sprintf("%08b", data[0]))
+		add("u8", strconv.Itoa(int(data[0])))
+		add("i8", strconv.Itoa(int(int8(data[0]))))
+	} else {
+		add("Binary", missing)
+		add("u8", missing)
+		add("i8", missing)
+	}
+	for _, size := range []int{2, 4, 8} {
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			suffix := " LE"
+			if order == binary.BigEndian {
+				suffix = " BE"
+			}
+			unsigned, signed := missing, missing
+			if len(data) >= size {
+				val := readWord(data[:size], order)
+				unsigned = strconv.FormatUint(val, 10)
+				signed = strconv.FormatInt(signedWord(val, size), 10)
+			}
+			add(fmt.Sprintf("u%d%s", size*8, suffix), unsigned)
+			add(fmt.Sprintf("i%d%s", size*8, suffix), signed)
+		}
+	}
+
+	// Floating point
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		suffix := " LE"
+		if order == binary.BigEndian {
+			suffix = " BE"
+		}
+		float, double := missing, missing
+		if len(data) >= 4 {
+			float = strconv.FormatFloat(float64(math.Float32frombits(order.Uint32(data))), 'g', -1, 32)
+		}
+		if len(data) >= 8 {
+			double = strconv.FormatFloat(math.Float64frombits(order.Uint64(data)), 'g', -1, 64)
+		}
+		add("float"+suffix, float)
+		add("double"+suffix, double)
+	}
+
+	// Timestamps (little-endian, seconds since 1970)
+	timeField := func(seconds int64) string {
+		return time.Unix(seconds, 0).UTC().Format("2006-01-02 15:04:05 UTC")
+	}
+	if len(data) >= 4 {
+		add("time_t (32-bit)", timeField(int64(int32(binary.LittleEndian.Uint32(data)))))
+	} else {
+		add("time_t (32-bit)", missing)
+	}
+	if len(data) >= 8 {
+		add("time_t (64-bit)", timeField(int64(binary.LittleEndian.Uint64(data))))
+	} else {
+		add("time_t (64-bit)", missing)
+	}
+
+	// Pointer
+	if len(data) >= 4 {
+		ptr := binary.LittleEndian.Uint32(data)
+		value := fmt.Sprintf("0x%08x", ptr)
+		if describePointer != nil {
+			if target := describePointer(ptr); target != "" {
+				value += "  " + target
+			}
+		}
+		add("Pointer", value)
+	} else {
+		add("Pointer", missing)
+	}
+
+	// Text
+	text := data[:min(len(data), textLen, MaxInspectText)]
+	ascii := missing
+	if len(text) > 0 {
+		var sb strings.Builder
+		for _, b := range text {
+			if b >= 0x20 && b < 0x7F {
+				sb.WriteByte(b)
+			} else {
+				sb.WriteByte('.')
+			}
+		}
+		ascii = sb.String()
+	}
+	add("ASCII", ascii)
+
+	utf := missing
+	if len(text) > 0 {
+		// A multi-byte character may be cut off at the end of the selection
+		valid := text
+		for len(valid) > 0 && !utf8.Valid(valid) && len(text)-len(valid) < utf8.UTFMax {
+			valid = valid[:len(valid)-1]
+		}
+		if utf8.Valid(valid) && len(valid) > 0 {
+			utf = strconv.Quote(string(valid))
+		} else {
+			utf = "(invalid UTF-8)"
+		}
+	}
+	add("UTF-8", utf)
+
+	return fields
+}
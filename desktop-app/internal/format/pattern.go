@@ -0,0 +1,124 @@
+package format
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// MaxPatternSize matches the firmware's PICOPEEKER_MAX_PATTERN_SIZE
+const MaxPatternSize = 64
+
+// Pattern is a byte pattern to search for. A mask bit of 0 means the bit can
+// have any value, so a 0x00 mask byte matches any byte.
+type Pattern struct {
+	Bytes []byte
+	Mask  []byte
+}
+
+// ParsePattern reads an IDA-style pattern. Bytes are hex, separated by spaces
+// or run together ("DEADBEEF"). "??" (or "?") matches any byte, and VALUE/MASK
+// compares only the mask bits, e.g. "0x1F/0x1F" or "80/F0":
+//
+//	DE AD ?? EF
+//	01 00 0x1F/0x1F ?? ?? 00 00
+func ParsePattern(text string) (Pattern, error) {
+	var p Pattern
+	for _, field := range strings.Fields(text) {
+		if value, mask, ok := strings.Cut(field, "/"); ok {
+			valueBytes, err := parsePatternHex(value)
+			if err != nil {
+				return Pattern{}, err
+			}
+			maskBytes, err := parsePatternHex(mask)
+			if err != nil {
+				return Pattern{}, err
+			}
+			if len(valueBytes) != len(maskBytes) {
+				return Pattern{}, fmt.Errorf("Value and mask in %q must be the same length", field)
+			}
+			for i := range valueBytes {
+				p.Bytes = append(p.Bytes, valueBytes[i]&maskBytes[i])
+				p.Mask = append(p.Mask, maskBytes[i])
+			}
+			continue
+		}
+
+		if field == "?" || field == "??" {
+			p.Bytes = append(p.Bytes, 0)
+			p.Mask = append(p.Mask, 0)
+			continue
+		}
+
+		// Hex run, possibly with ?? wildcards inside (e.g. "DEAD??EF")
+		field = strings.TrimPrefix(strings.TrimPrefix(field, "0x"), "0X")
+		if len(field)%2 != 0 {
+			return Pattern{}, fmt.Errorf("Hex pattern must have even number of characters (e.g., DEADBEEF)")
+		}
+		for i := 0; i < len(field); i += 2 {
+			pair := field[i : i+2]
+			if pair == "??" {
+				p.Bytes = append(p.Bytes, 0)
+				p.Mask = append(p.Mask, 0)
+				continue
+			}
+			val, err := strconv.ParseUint(pair, 16, 8)
+			if err != nil {
+				return Pattern{}, fmt.Errorf("Invalid hex pattern %q. Use 0-9, A-F, ?? or VALUE/MASK (e.g., DE AD ?? EF)", pair)
+			}
+			p.Bytes = append(p.Bytes, byte(val))
+			p.Mask = append(p.Mask, 0xFF)
+		}
+	}
+
+	if len(p.Bytes) == 0 {
+		return Pattern{}, fmt.Errorf("Hex pattern cannot be empty")
+	}
+	if len(p.Bytes) > MaxPatternSize {
+		return Pattern{}, fmt.Errorf("Pattern is %d bytes, the firmware accepts up to %d", len(p.Bytes), MaxPatternSize)
+	}
+	for _, m := range p.Mask {
+		if m != 0 {
+			return p, nil
+		}
+	}
+	return Pattern{}, fmt.Errorf("Pattern must contain at least one byte that is not ??")
+}
+
+// parsePatternHex reads one side of a VALUE/MASK pair
+func parsePatternHex(text string) ([]byte, error) {
+	hex := strings.TrimPrefix(strings.TrimPrefix(text, "0x"), "0X")
+	if hex == "" || len(hex)%2 != 0 {
+		return nil, fmt.Errorf("Invalid masked byte %q (e.g., 0x1F/0x1F)", text)
+	}
+	var out []byte
+	for i := 0; i < len(hex); i += 2 {
+		val, err := strconv.ParseUint(hex[i:i+2], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid masked byte %q (e.g., 0x1F/0x1F)", text)
+		}
+		out = append(out, byte(val))
+	}
+	return out, nil
+}
+
+// Masked reports whether any byte of the pattern is a wildcard or partly masked
+func (p Pattern) Masked() bool {
+	for _, m := range p.Mask {
+		if m != 0xFF {
+			return true
+		}
+	}
+	return false
+}
+
+// Wire encodes the pattern for the SEARCH commands: plain hex for exact
+// patterns (understood by older firmware) or HEX/MASK
+func (p Pattern) Wire() string {
+	hex := fmt.Sprintf("%X", p.Bytes)
+	if !p.Masked() {
+		return hex
+	}
+	return fmt.Sprintf("%s/%X", hex, p.Mask)
+}
+
@@ -0,0 +1,111 @@
+package format
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// StringMatch is a run of printable text found in memory
+type StringMatch struct {
+	Address  uint32
+	Text     string
+	Encoding string // "ascii" or "utf16le"
+}
+
+// DefaultMinStringLength matches the Unix strings tool
+const DefaultMinStringLength = 4
+
+func isPrintable(b byte) bool {
+	return (b >= 0x20 && b < 0x7F) || b == '\t'
+}
+
+// ExtractStrings finds printable ASCII runs and UTF-16LE runs (printable ASCII
+// characters followed by a zero byte) of at least minLen characters, sorted by address
+func ExtractStrings(data []byte, startAddr uint32, minLen int) []StringMatch {
+	if minLen < 1 {
+		minLen = 1
+	}
+	var matches []StringMatch
+
+	// ASCII
+	start := -1
+	for i := 0; i <= len(data); i++ {
+		if i < len(data) && isPrintable(data[i]) {
+			if start == -1 {
+				start = i
+			}
+			continue
+		}
+		if start != -1 && i-start >= minLen {
+			matches = append(matches, StringMatch{Address: startAddr + uint32(start), Text: string(data[start:i]), Encoding: "ascii"})
+		}
+		start = -1
+	}
+
+	// UTF-16LE, at both byte alignments
+	for align := 0; align < 2; align++ {
+		var run []byte
+		runStart := 0
+		flush := func() {
+			if len(run) >= minLen {
+				matches = append(matches, StringMatch{Address: startAddr + uint32(runStart), Text: string(run), Encoding: "utf16le"})
+			}
+			run = nil
+		}
+		for i := align; i+1 < len(data); i += 2 {
+			if isPrintable(data[i]) && data[i+1] == 0 {
+				if run == nil {
+					runStart = i
+				}
+				run = append(run, data[i])
+				continue
+			}
+			flush()
+		}
+		flush()
+	}
+
+	// Stable, so ASCII comes before UTF-16 at the same address
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Address < matches[j].Address
+	})
+	return matches
+}
+
+// FilterStrings returns the matches containing filter (case-insensitive)
+func FilterStrings(matches []StringMatch, filter string) []StringMatch {
+	if filter == "" {
+		return matches
+	}
+	filter = strings.ToLower(filter)
+
+	var filtered []StringMatch
+	for _, match := range matches {
+		if strings.Contains(strings.ToLower(match.Text), filter) {
+			filtered = append(filtered, match)
+		}
+	}
+	return filtered
+}
+
+// FormatStrings lists the matches like `strings -t x`, marking UTF-16 ones
+func FormatStrings(matches []StringMatch) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("=== Strings (%d found) ===\n\n", len(matches)))
+	for _, match := range matches {
+		sb.WriteString(FormatStringMatch(match))
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n===END===\n")
+	return sb.String()
+}
+
+// FormatStringMatch returns one line of a strings listing
+func FormatStringMatch(match StringMatch) string {
+	marker := "     "
+	if match.Encoding == "utf16le" {
+		marker = "[u16]"
+	}
+	return fmt.Sprintf("%08x %s %s", match.Address, marker, match.Text)
+}
@@ -0,0 +1,144 @@
+package pointers
+
+import (
+	"github.com/MironCo/picopeeker/internal/format"
+	"encoding/binary"
+	"fmt"
+	"sort"
+)
+
+// Snapshot is memory read from the device for a scan, e.g. all of SRAM
+type Snapshot []format.MemoryBlock
+
+// Read32 returns the little-endian word at addr, if the snapshot covers it
+func (s Snapshot) Read32(addr uint32) (uint32, bool) {
+	for _, block := range s {
+		if addr >= block.Address && uint64(addr)+4 <= uint64(block.Address)+uint64(len(block.Data)) {
+			offset := addr - block.Address
+			return binary.LittleEndian.Uint32(block.Data[offset : offset+4]), true
+		}
+	}
+	return 0, false
+}
+
+// Path is a pointer chain: start at Base, then for each offset read the
+// pointer at the current address and add the offset to it.
+type Path struct {
+	Base    uint32
+	Offsets []int32
+}
+
+// Resolve follows the path through the snapshot and returns the address it ends at
+func (p Path) Resolve(s Snapshot) (uint32, bool) {
+	addr := p.Base
+	for _, offset := range p.Offsets {
+		value, ok := s.Read32(addr)
+		if !ok {
+			return 0, false
+		}
+		addr = value + uint32(offset)
+	}
+	return addr, true
+}
+
+// Format shows the path like Cheat Engine, e.g. "[[0x20001000]+0x10]+0x4".
+// name, if set, replaces the base address with a symbol name.
+func (p Path) Format(name func(uint32) string) string {
+	text := fmt.Sprintf("0x%08x", p.Base)
+	if name != nil {
+		if sym := name(p.Base); sym != "" {
+			text = sym
+		}
+	}
+	for _, offset := range p.Offsets {
+		if offset < 0 {
+			text = fmt.Sprintf("[%s]-0x%x", text, -int64(offset))
+		} else {
+			text = fmt.Sprintf("[%s]+0x%x", text, offset)
+		}
+	}
+	return text
+}
+
+// Options control a pointer scan
+type Options struct {
+	MaxOffset uint32 // Pointers may point up to this many bytes either side of their target
+	MaxDepth  int    // Longest chain, 1 for direct pointers
+	MaxPaths  int    // Stop after this many paths
+}
+
+// pointer is an aligned word in the snapshot and the value stored there
+type pointer struct {
+	value   uint32
+	address uint32
+}
+
+// Scan finds pointer paths through the snapshot that end at target. Shorter
+// paths come first. The bool reports whether MaxPaths cut the scan short.
+func Scan(s Snapshot, target uint32, opts Options) ([]Path, bool) {
+	// Every aligned word is a potential pointer, sorted by value so the ones
+	// near a target can be found with a binary search
+	var index []pointer
+	for _, block := range s {
+		start := (4 - block.Address%4) % 4
+		for i := int(start); i+4 <= len(block.Data); i += 4 {
+			value := binary.LittleEndian.Uint32(block.Data[i : i+4])
+			if value == 0 || value == 0xFFFFFFFF {
+				continue
+			}
+			index = append(index, pointer{value: value, address: block.Address + uint32(i)})
+		}
+	}
+	sort.Slice(index, func(i, j int) bool {
+		return index[i].value < index[j].value
+	})
+
+	type node struct {
+		address uint32
+		offsets []int32 // Chain from this address to the target
+	}
+
+	var paths []Path
+	frontier := []node{{address: target}}
+	visited := map[uint32]bool{target: true}
+
+	for depth := 1; depth <= opts.MaxDepth && len(frontier) > 0; depth++ {
+		var next []node
+		for _, n := range frontier {
+			low := uint64(n.address) - min(uint64(n.address), uint64(opts.MaxOffset))
+			high := uint64(n.address) + uint64(opts.MaxOffset)
+
+			i := sort.Search(len(index), func(i int) bool {
+				return uint64(index[i].value) >= low
+			})
+			for ; i < len(index) && uint64(index[i].value) <= high; i++ {
+				ptr := index[i]
+				offsets := append([]int32{int32(n.address - ptr.value)}, n.offsets...)
+				paths = append(paths, Path{Base: ptr.address, Offsets: offsets})
+				if opts.MaxPaths > 0 && len(paths) >= opts.MaxPaths {
+					return paths, true
+				}
+
+				// Each address is only extended once, along its shortest chain
+				if !visited[ptr.address] {
+					visited[ptr.address] = true
+					next = append(next, node{address: ptr.address, offsets: offsets})
+				}
+			}
+		}
+		frontier = next
+	}
+	return paths, false
+}
+
+// Filter keeps the paths that still end at target in a new snapshot, e.g. one
+// taken after the device was reset
+func Filter(paths []Path, s Snapshot, target uint32) []Path {
+	var kept []Path
+	for _, path := range paths {
+		if addr, ok := path.Resolve(s); ok && addr == target {
+			kept = append(kept, path)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,103 @@
+package pointers
+
+import (
+	"github.com/MironCo/picopeeker/internal/format"
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// snapshot returns 0x100 bytes of SRAM at 0x20000000 with the given words set
+func snapshot(words map[uint32]uint32) Snapshot {
+	data := make([]byte, 0x100)
+	for addr, value := range words {
+		binary.LittleEndian.PutUint32(data[addr-0x20000000:], value)
+	}
+	return Snapshot{{Address: 0x20000000, Data: data}}
+}
+
+// The target is a field at +0x10 in a struct at 0x20000070. One pointer
+// points at the struct, one 0x10 past the target, and one is a second level
+// pointer 8 bytes before the first.
+var before = snapshot(map[uint32]uint32{
+	0x20000010: 0x20000070,
+	0x20000020: 0x20000090,
+	0x20000030: 0x20000008,
+})
+
+var allPaths = []Path{
+	{Base: 0x20000010, Offsets: []int32{0x10}},
+	{Base: 0x20000020, Offsets: []int32{-0x10}},
+	{Base: 0x20000030, Offsets: []int32{0x8, 0x10}},
+}
+
+func TestScan(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          Options
+		want          []Path
+		wantTruncated bool
+	}{
+		{"all paths", Options{MaxOffset: 0x10, MaxDepth: 2}, allPaths, false},
+		{"direct pointers only", Options{MaxOffset: 0x10, MaxDepth: 1}, allPaths[:2], false},
+		{"smaller offsets", Options{MaxOffset: 0xF, MaxDepth: 2}, nil, false},
+		{"MaxPaths", Options{MaxOffset: 0x10, MaxDepth: 2, MaxPaths: 2}, allPaths[:2], true},
+	}
+
+	for _, tt := range tests {
+		got, truncated := Scan(before, 0x20000080, tt.opts)
+		if !reflect.DeepEqual(got, tt.want) || truncated != tt.wantTruncated {
+			t.Errorf("%s: Scan = %v (truncated %v), want %v (truncated %v)", tt.name, got, truncated, tt.want, tt.wantTruncated)
+		}
+	}
+
+	for _, path := range allPaths {
+		if addr, ok := path.Resolve(before); !ok || addr != 0x20000080 {
+			t.Errorf("%s resolves to 0x%08x (%v), want 0x20000080", path.Format(nil), addr, ok)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	names := func(addr uint32) string {
+		if addr == 0x20000030 {
+			return "g_state"
+		}
+		return ""
+	}
+	tests := []struct {
+		path Path
+		want string
+	}{
+		{allPaths[0], "[0x20000010]+0x10"},
+		{allPaths[1], "[0x20000020]-0x10"},
+		{allPaths[2], "[[g_state]+0x8]+0x10"},
+	}
+	for _, tt := range tests {
+		if got := tt.path.Format(names); got != tt.want {
+			t.Errorf("Format = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestFilter(t *testing.T) {
+	// After a reset the struct is at 0x20000074, the second pointer still
+	// points where the target used to be, and the second level pointer now
+	// points at unrelated memory
+	after := snapshot(map[uint32]uint32{
+		0x20000010: 0x20000074,
+		0x20000020: 0x20000090,
+		0x20000030: 0x20000050,
+	})
+
+	got := Filter(allPaths, after, 0x20000084)
+	if want := allPaths[:1]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Filter = %v, want %v", got, want)
+	}
+
+	// A path through memory the snapshot doesn't cover is dropped too
+	partial := Snapshot{format.MemoryBlock{Address: 0x20000000, Data: after[0].Data[:0x20]}}
+	if got := Filter(allPaths, partial, 0x20000084); !reflect.DeepEqual(got, allPaths[:1]) {
+		t.Errorf("Filter with a partial snapshot = %v, want %v", got, allPaths[:1])
+	}
+}
@@ -0,0 +1,177 @@
+package serial
+
+import (
+	"github.com/MironCo/picopeeker/internal/config"
+	"github.com/MironCo/picopeeker/internal/util"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeviceInfo holds the identification reported by the INFO command
+type DeviceInfo struct {
+	Chip            string // "RP2040" or "RP2350"
+	Arch            string // "arm" or "riscv", "" if the firmware doesn't report it
+	ChipID          uint32 // SYSINFO CHIP_ID register
+	SDKVersion      string
+	FlashSize       uint32          // Bytes, 0 if not reported
+	FlashSizeSource string          // "jedec" if read from the flash chip, "board" if from the board header
+	SRAMSize        uint32          // Bytes, 0 if not reported
+	PSRAMSize       uint32          // Bytes, 0 if the firmware wasn't built with PSRAM
+	BoardID         string          // Unique board ID (also used as the USB serial number)
+	Regions         []config.Region // Readable regions, including any the firmware was configured with
+}
+
+// Device is a serial port that answered as a PicoPeeker target
+type Device struct {
+	Port      string
+	USB       util.PortInfo // USB descriptor details from port enumeration
+	Info      DeviceInfo
+	Landmarks string
+	Err       error // Set if the port could not be probed
+}
+
+// FetchInfo opens the port just long enough to send the INFO command
+func FetchInfo(portName string) (DeviceInfo, error) {
+	return fetchInfo(oneShot(portName))
+}
+
+// FetchInfo sends the INFO command over the session
+func (s *Session) FetchInfo() (DeviceInfo, error) {
+	return fetchInfo(s.Exec)
+}
+
+func fetchInfo(exec execFunc) (DeviceInfo, error) {
+	result, err := exec("INFO\n", "END_INFO", 2*time.Second)
+	if err != nil {
+		return DeviceInfo{}, err
+	}
+
+	info, ok := ParseInfo(result)
+	if !ok {
+		return DeviceInfo{}, fmt.Errorf("no INFO response (firmware may be too old)")
+	}
+	return info, nil
+}
+
+// ParseInfo extracts the key=value lines between INFO: and END_INFO
+func ParseInfo(data string) (DeviceInfo, bool) {
+	start := strings.Index(data, "INFO:")
+	if start == -1 {
+		return DeviceInfo{}, false
+	}
+	data = data[start+len("INFO:"):]
+	if end := strings.Index(data, "END_INFO"); end != -1 {
+		data = data[:end]
+	}
+
+	info := DeviceInfo{}
+	for _, line := range strings.Split(data, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found {
+			continue
+		}
+		switch key {
+		case "chip":
+			info.Chip = value
+		case "arch":
+			info.Arch = value
+		case "chip_id":
+			info.ChipID = parseInfoNumber(value)
+		case "sdk":
+			info.SDKVersion = value
+		case "flash_size":
+			info.FlashSize = parseInfoNumber(value)
+		case "flash_size_source":
+			info.FlashSizeSource = value
+		case "sram_size":
+			info.SRAMSize = parseInfoNumber(value)
+		case "psram_size":
+			info.PSRAMSize = parseInfoNumber(value)
+		case "board_id":
+			info.BoardID = value
+		case "region":
+			if region, ok := parseInfoRegion(value); ok {
+				info.Regions = append(info.Regions, region)
+			}
+		}
+	}
+	return info, true
+}
+
+// parseInfoRegion parses a "NAME:0xSTART:0xEND" region line
+func parseInfoRegion(value string) (config.Region, bool) {
+	parts := strings.Split(value, ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return config.Region{}, false
+	}
+	start, err := strconv.ParseUint(parts[1], 0, 32)
+	if err != nil {
+		return config.Region{}, false
+	}
+	end, err := strconv.ParseUint(parts[2], 0, 32)
+	if err != nil || end <= start {
+		return config.Region{}, false
+	}
+	return config.Region{Name: parts[0], Start: uint32(start), End: uint32(end), Access: config.AccessRead}, true
+}
+
+// parseInfoNumber parses a decimal or 0x-prefixed hex value, returning 0 if invalid
+func parseInfoNumber(value string) uint32 {
+	val, err := strconv.ParseUint(value, 0, 32)
+	if err != nil {
+		return 0
+	}
+	return uint32(val)
+}
+
+// ProbeDevice checks whether a PicoPeeker is listening on the port.
+// Firmware without the INFO command is still detected through LANDMARKS.
+func ProbeDevice(usbPort util.PortInfo) Device {
+	portName := usbPort.Name
+	device := Device{Port: portName, USB: usbPort}
+	device.Info.Chip = usbPort.Chip
+	device.Info.BoardID = usbPort.SerialNumber
+
+	// Prefer what the firmware reports, keep the USB details as a fallback
+	info, infoErr := FetchInfo(portName)
+	if infoErr == nil {
+		if info.Chip != "" {
+			device.Info.Chip = info.Chip
+		}
+		if info.BoardID != "" {
+			device.Info.BoardID = info.BoardID
+		}
+	}
+
+	landmarks, err := FetchLandmarks(portName)
+	if err != nil {
+		device.Err = err
+		return device
+	}
+	if infoErr != nil && strings.HasPrefix(landmarks, "No landmarks") {
+		device.Err = fmt.Errorf("no PicoPeeker response")
+		return device
+	}
+	device.Landmarks = landmarks
+	return device
+}
+
+// ProbeDevices probes all ports in parallel, preserving their order
+func ProbeDevices(ports []util.PortInfo) []Device {
+	devices := make([]Device, len(ports))
+
+	var wg sync.WaitGroup
+	for i, port := range ports {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			devices[i] = ProbeDevice(port)
+		}()
+	}
+	wg.Wait()
+
+	return devices
+}
@@ -0,0 +1,182 @@
+package serial
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// Exchange is one command sent to the device and the raw response to it
+type Exchange struct {
+	Time     time.Time `json:"time"`
+	Command  string    `json:"command"`
+	Response string    `json:"response"`
+	Error    string    `json:"error,omitempty"`
+	Duration int64     `json:"duration_ms"`
+}
+
+// Recording is a captured serial session. It can be saved to a session file,
+// attached to bug reports, replayed into the app, or used as a parser fixture.
+type Recording struct {
+	Version   int        `json:"version"`
+	Port      string     `json:"port"`
+	Started   time.Time  `json:"started"`
+	Exchanges []Exchange `json:"exchanges"`
+}
+
+// recordingVersion is bumped if the session file format changes
+const recordingVersion = 1
+
+// LoadRecording reads a session file
+func LoadRecording(path string) (*Recording, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read session file: %w", err)
+	}
+	defer file.Close()
+	return ReadRecording(file)
+}
+
+// ReadRecording decodes a session file
+func ReadRecording(r io.Reader) (*Recording, error) {
+	rec := &Recording{}
+	if err := json.NewDecoder(r).Decode(rec); err != nil {
+		return nil, fmt.Errorf("invalid session file: %w", err)
+	}
+	if rec.Version > recordingVersion {
+		return nil, fmt.Errorf("session file version %d is newer than supported (%d)", rec.Version, recordingVersion)
+	}
+	return rec, nil
+}
+
+// Save writes the recording to a session file
+func (r *Recording) Save(path string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := r.Write(file); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Write encodes the recording as indented JSON
+func (r *Recording) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// Find returns the first exchange for the command (including its trailing newline)
+func (r *Recording) Find(command string) (Exchange, bool) {
+	for _, exchange := range r.Exchanges {
+		if exchange.Command == command {
+			return exchange, true
+		}
+	}
+	return Exchange{}, false
+}
+
+// StartRecording begins capturing every command and response on the session
+func (s *Session) StartRecording() {
+	s.recMu.Lock()
+	defer s.recMu.Unlock()
+
+	s.recording = &Recording{
+		Version: recordingVersion,
+		Port:    s.Port(),
+		Started: time.Now(),
+	}
+}
+
+// StopRecording ends the capture and returns what was recorded, or nil if the
+// session wasn't recording
+func (s *Session) StopRecording() *Recording {
+	s.recMu.Lock()
+	defer s.recMu.Unlock()
+
+	rec := s.recording
+	s.recording = nil
+	return rec
+}
+
+// IsRecording reports whether the session is capturing commands
+func (s *Session) IsRecording() bool {
+	s.recMu.Lock()
+	defer s.recMu.Unlock()
+	return s.recording != nil
+}
+
+// record appends an exchange if the session is recording
+func (s *Session) record(start time.Time, command, response string, err error) {
+	s.recMu.Lock()
+	defer s.recMu.Unlock()
+
+	if s.recording == nil {
+		return
+	}
+	exchange := Exchange{
+		Time:     start,
+		Command:  command,
+		Response: response,
+		Duration: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		exchange.Error = err.Error()
+	}
+	s.recording.Exchanges = append(s.recording.Exchanges, exchange)
+}
+
+// replayer answers commands from a recording instead of a device
+type replayer struct {
+	mu     sync.Mutex
+	rec    *Recording
+	cursor int // Index after the last exchange served
+}
+
+// NewReplaySession creates a session that answers from a recording as if a
+// device were attached. Commands are matched in recorded order, so repeated
+// reads of the same address return the values captured at that point.
+func NewReplaySession(rec *Recording) *Session {
+	s := &Session{
+		portName: fmt.Sprintf("replay:%s", rec.Port),
+		state:    Connected,
+		replay:   &replayer{rec: rec},
+		done:     make(chan struct{}),
+	}
+	return s
+}
+
+// IsReplay reports whether the session is replaying a recording
+func (s *Session) IsReplay() bool {
+	return s.replay != nil
+}
+
+// exec returns the next recorded response to the command
+func (r *replayer) exec(command string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	exchanges := r.rec.Exchanges
+	for i := 0; i < len(exchanges); i++ {
+		// Search forward from the cursor first, then wrap around
+		idx := (r.cursor + i) % len(exchanges)
+		if exchanges[idx].Command != command {
+			continue
+		}
+
+		r.cursor = idx + 1
+		exchange := exchanges[idx]
+		if exchange.Error != "" {
+			return exchange.Response, fmt.Errorf("%s", exchange.Error)
+		}
+		return exchange.Response, nil
+	}
+
+	return "", fmt.Errorf("no recorded response for %q", command)
+}
@@ -0,0 +1,193 @@
+package serial
+
+import (
+	"github.com/MironCo/picopeeker/internal/config"
+	"github.com/MironCo/picopeeker/internal/format"
+	"bytes"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testdata/session.json is a recorded session with an RP2350: INFO, a normal
+// READ, a READ clamped at the end of SRAM, a READ outside every region, two
+// pages of a SEARCHPAGE, the SEARCHCOUNT for it, a SEARCHRANGE of the boot ROM,
+// and SEARCHRANGE and SEARCHPAGE errors.
+func loadSession(t *testing.T) *Recording {
+	t.Helper()
+	rec, err := LoadRecording("testdata/session.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rec
+}
+
+func response(t *testing.T, rec *Recording, command string) string {
+	t.Helper()
+	exchange, ok := rec.Find(command)
+	if !ok {
+		t.Fatalf("no %q in the recording", command)
+	}
+	return exchange.Response
+}
+
+func TestParseInfo(t *testing.T) {
+	rec := loadSession(t)
+	info, ok := ParseInfo(response(t, rec, "INFO\n"))
+	if !ok {
+		t.Fatal("ParseInfo found no INFO response")
+	}
+
+	want := DeviceInfo{
+		Chip:            "RP2350",
+		Arch:            "arm",
+		ChipID:          0x20004927,
+		SDKVersion:      "2.1.1",
+		FlashSize:       4 * 1024 * 1024,
+		FlashSizeSource: "jedec",
+		SRAMSize:        520 * 1024,
+		BoardID:         "E66368254F2B5A2F",
+		Regions: []config.Region{
+			{Name: "ROM", Start: 0x00000000, End: 0x00004000, Access: config.AccessRead},
+			{Name: "Flash", Start: 0x10000000, End: 0x10400000, Access: config.AccessRead},
+			{Name: "SRAM", Start: 0x20000000, End: 0x20082000, Access: config.AccessRead},
+			{Name: "Flash (uncached)", Start: 0x14000000, End: 0x14400000, Access: config.AccessRead},
+			{Name: "Peripherals", Start: 0x40000000, End: 0x60000000, Access: config.AccessRead},
+		},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("ParseInfo = %+v, want %+v", info, want)
+	}
+}
+
+func TestParseHexDump(t *testing.T) {
+	rec := loadSession(t)
+	tests := []struct {
+		command string
+		want    []byte
+	}{
+		{
+			command: "READ:0x20000100:32\n",
+			want:    []byte("Hello, PicoPeeker!\x00\x00\x01\x02\x03\x04\xde\xad\xbe\xef\x10\x20\x00\x20"),
+		},
+		{
+			// The clamp warning before the dump must not be read as data
+			command: "READ:0x20081ff0:32\n",
+			want:    []byte{0x00, 0x00, 0x00, 0x00, 0x78, 0x56, 0x34, 0x12, 0xff, 0xff, 0xff, 0xff, 0x00, 0x01, 0x00, 0x00},
+		},
+	}
+
+	for _, tt := range tests {
+		got := format.ParseHexDump(response(t, rec, tt.command))
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("ParseHexDump(%q) = % x, want % x", strings.TrimSpace(tt.command), got, tt.want)
+		}
+	}
+}
+
+func TestReadBlock(t *testing.T) {
+	session := NewReplaySession(loadSession(t))
+
+	block, err := session.ReadBlock(0x20081ff0, 32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Address != 0x20081ff0 || len(block.Data) != 16 {
+		t.Errorf("clamped read = %d bytes at 0x%08x, want 16 bytes at 0x20081ff0", len(block.Data), block.Address)
+	}
+
+	_, err = session.ReadBlock(0x30000000, 16)
+	if err == nil || err.Error() != "ERROR: Address out of valid range" {
+		t.Errorf("read outside every region: err = %v, want the firmware's error line", err)
+	}
+}
+
+func TestParseSearchPage(t *testing.T) {
+	rec := loadSession(t)
+	tests := []struct {
+		command string
+		want    SearchPage
+		wantErr string
+	}{
+		{
+			command: "SEARCHPAGE:0x20000000:0x20082000:2:48656C6C6F\n",
+			want:    SearchPage{Hits: []uint32{0x20000100, 0x20000400}, More: true, Next: 0x20000401},
+		},
+		{
+			command: "SEARCHPAGE:0x20000401:0x20082000:2:48656C6C6F\n",
+			want:    SearchPage{Hits: []uint32{0x20001a20}},
+		},
+		{
+			command: "SEARCHPAGE:0x30000000:0x30001000:100:48656C6C6F\n",
+			wantErr: "ERROR: Start address out of valid range",
+		},
+	}
+
+	for _, tt := range tests {
+		name := strings.TrimSpace(tt.command)
+		got, err := ParseSearchPage(response(t, rec, tt.command))
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: err = %v, want %q", name, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if !slices.Equal(got.Hits, tt.want.Hits) || got.More != tt.want.More || got.Next != tt.want.Next {
+			t.Errorf("%s: got %+v, want %+v", name, got, tt.want)
+		}
+	}
+}
+
+func TestSearchRange(t *testing.T) {
+	session := NewReplaySession(loadSession(t))
+	hits, truncated, err := session.SearchRange(0x00000000, 0x00004000, "4D75")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []uint32{0x00000010, 0x0000012c}; !slices.Equal(hits, want) || truncated {
+		t.Errorf("SearchRange = %x (truncated %v), want %x", hits, truncated, want)
+	}
+
+	_, _, err = session.SearchRange(0x00003fff, 0x00004000, "4D75")
+	if err == nil || err.Error() != "ERROR: Range is shorter than the pattern" {
+		t.Errorf("range shorter than the pattern: err = %v, want the firmware's error line", err)
+	}
+
+	_, truncated, err = ParseSearchRange("FOUND: 0x20000000\n(stopping after 1 matches)\nTotal matches in RANGE: 1\n\n===END===\n")
+	if err != nil || !truncated {
+		t.Errorf("result limit: truncated = %v, err = %v", truncated, err)
+	}
+}
+
+func TestSearchCount(t *testing.T) {
+	session := NewReplaySession(loadSession(t))
+	count, err := session.SearchCount(0x20000000, 0x20082000, "48656C6C6F")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("SearchCount = %d, want 3", count)
+	}
+
+	if _, err := ParseSearchCount("ERROR: Range is shorter than the pattern\n===END===\n"); err == nil {
+		t.Error("expected the firmware's error for a bad range")
+	}
+	if _, err := ParseSearchCount("ERROR: Invalid command\n"); err == nil {
+		t.Error("expected an error from firmware without SEARCHCOUNT")
+	}
+}
+
+func TestParseSearchPageIncomplete(t *testing.T) {
+	// Firmware without SEARCHPAGE answers with its usual error and no trailer
+	if _, err := ParseSearchPage("ERROR: Invalid command\n"); err == nil {
+		t.Error("expected an error for a response without DONE or MORE")
+	}
+	if _, err := ParseSearchPage("FOUND: 0x20000100\n"); err == nil {
+		t.Error("expected an error for a response cut off before DONE")
+	}
+}
@@ -0,0 +1,146 @@
+package serial
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultSearchPageSize is how many hits SearchPage asks for at a time
+const DefaultSearchPageSize = 100
+
+// SearchPage is one page of hits from a SEARCHPAGE command
+type SearchPage struct {
+	Hits []uint32
+	More bool   // The range has more hits after this page
+	Next uint32 // Where to resume when More is set
+}
+
+// SearchPage returns up to count hits for the pattern in [start, end). Pass
+// Next as start to fetch the following page.
+func (s *Session) SearchPage(start, end uint32, pattern string, count int) (SearchPage, error) {
+	// A page may scan the whole range: allow ~30us per byte on top of the usual timeout
+	timeout := 30*time.Second + time.Duration(end-start)*30*time.Microsecond
+	command := fmt.Sprintf("SEARCHPAGE:0x%08x:0x%08x:%d:%s\n", start, end, count, pattern)
+	result, err := s.Exec(command, "===END===", timeout)
+	if err != nil {
+		return SearchPage{}, err
+	}
+	return ParseSearchPage(result)
+}
+
+// ParseSearchPage reads the FOUND lines and the MORE/DONE trailer of a SEARCHPAGE response
+func ParseSearchPage(data string) (SearchPage, error) {
+	var page SearchPage
+	complete := false
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		key, value, ok := strings.Cut(line, ":")
+		value = strings.TrimSpace(value)
+
+		switch {
+		case line == "DONE":
+			complete = true
+		case !ok:
+			continue
+		case key == "ERROR":
+			return SearchPage{}, fmt.Errorf("%s", line)
+		case key == "FOUND" || key == "MORE":
+			addr, err := strconv.ParseUint(strings.TrimPrefix(value, "0x"), 16, 32)
+			if err != nil {
+				return SearchPage{}, fmt.Errorf("invalid address in %q", line)
+			}
+			if key == "FOUND" {
+				page.Hits = append(page.Hits, uint32(addr))
+			} else {
+				page.More, page.Next = true, uint32(addr)
+				complete = true
+			}
+		}
+	}
+
+	if !complete {
+		return SearchPage{}, fmt.Errorf("incomplete search response (does the firmware support SEARCHPAGE?)")
+	}
+	return page, nil
+}
+
+// SearchRange returns the hits for the pattern in [start, end). The range must
+// start in a readable region, and the firmware clamps it to the end of that
+// region. truncated is set if the firmware stopped at its result limit
+// (PICOPEEKER_MAX_SEARCH_RESULTS); SearchPage has no such limit.
+func (s *Session) SearchRange(start, end uint32, pattern string) (hits []uint32, truncated bool, err error) {
+	timeout := 30*time.Second + time.Duration(end-start)*30*time.Microsecond
+	command := fmt.Sprintf("SEARCHRANGE:0x%08x:0x%08x:%s\n", start, end, pattern)
+	result, err := s.Exec(command, "===END===", timeout)
+	if err != nil {
+		return nil, false, err
+	}
+	return ParseSearchRange(result)
+}
+
+// ParseSearchRange reads the FOUND lines of a SEARCHRANGE response, which ends
+// with a "Total matches" line once the whole range has been searched
+func ParseSearchRange(data string) (hits []uint32, truncated bool, err error) {
+	complete := false
+
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		key, value, _ := strings.Cut(line, ":")
+
+		switch {
+		case strings.HasPrefix(line, "Total matches"):
+			complete = true
+		case strings.HasPrefix(line, "(stopping after"):
+			truncated = true
+		case key == "ERROR":
+			return nil, false, fmt.Errorf("%s", line)
+		case key == "FOUND":
+			addr, err := strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(value), "0x"), 16, 32)
+			if err != nil {
+				return nil, false, fmt.Errorf("invalid address in %q", line)
+			}
+			hits = append(hits, uint32(addr))
+		}
+	}
+
+	if !complete {
+		return nil, false, fmt.Errorf("incomplete search response (does the firmware support SEARCHRANGE?)")
+	}
+	return hits, truncated, nil
+}
+
+// SearchCount returns how many hits the pattern has in [start, end). It scans
+// the whole range, so it takes as long as paging through every hit.
+func (s *Session) SearchCount(start, end uint32, pattern string) (int, error) {
+	timeout := 30*time.Second + time.Duration(end-start)*30*time.Microsecond
+	command := fmt.Sprintf("SEARCHCOUNT:0x%08x:0x%08x:%s\n", start, end, pattern)
+	result, err := s.Exec(command, "===END===", timeout)
+	if err != nil {
+		return 0, err
+	}
+	return ParseSearchCount(result)
+}
+
+// ParseSearchCount reads the COUNT line of a SEARCHCOUNT response
+func ParseSearchCount(data string) (int, error) {
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "ERROR":
+			return 0, fmt.Errorf("%s", strings.TrimSpace(line))
+		case "COUNT":
+			count, err := strconv.ParseUint(strings.TrimSpace(value), 10, 32)
+			if err != nil {
+				return 0, fmt.Errorf("invalid count in %q", strings.TrimSpace(line))
+			}
+			return int(count), nil
+		}
+	}
+	return 0, fmt.Errorf("no search count in the response (does the firmware support SEARCHCOUNT?)")
+}
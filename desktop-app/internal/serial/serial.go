@@ -1,50 +1,41 @@
 package serial
 
 import (
+	"github.com/MironCo/picopeeker/internal/format"
+	"errors"
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	goserial "go.bug.st/serial"
 )
 
-func FetchLandmarks(portName string) (string, error) {
-	mode := &goserial.Mode{
-		BaudRate: 115200,
-	}
+// execFunc runs a single command and returns the raw response
+type execFunc func(command, endMarker string, timeout time.Duration) (string, error)
 
-	port, err := goserial.Open(portName, mode)
-	if err != nil {
-		return "", fmt.Errorf("failed to open port: %w", err)
+// oneShot returns an execFunc that opens the port for a single command
+func oneShot(portName string) execFunc {
+	return func(command, endMarker string, timeout time.Duration) (string, error) {
+		return runCommand(portName, command, endMarker, timeout)
 	}
-	defer port.Close()
+}
 
-	// Clear any initial data
-	time.Sleep(200 * time.Millisecond)
-	buf := make([]byte, 4096)
-	port.SetReadTimeout(100 * time.Millisecond)
-	port.Read(buf) // Discard
+// FetchLandmarks opens the port just long enough to read the landmarks
+func FetchLandmarks(portName string) (string, error) {
+	return fetchLandmarks(oneShot(portName))
+}
 
-	// Send LANDMARKS command
-	_, err = port.Write([]byte("LANDMARKS\n"))
-	if err != nil {
-		return "", fmt.Errorf("failed to send command: %w", err)
-	}
+// FetchLandmarks reads the landmarks over the session
+func (s *Session) FetchLandmarks() (string, error) {
+	return fetchLandmarks(s.Exec)
+}
 
-	// Read response
-	result := ""
-	startTime := time.Now()
-	for time.Since(startTime) < 2*time.Second {
-		port.SetReadTimeout(100 * time.Millisecond)
-		n, _ := port.Read(buf)
-		if n > 0 {
-			result += string(buf[:n])
-			// Check if we got the end marker
-			if strings.Contains(result, "END_LANDMARKS") {
-				break
-			}
-		}
+func fetchLandmarks(exec execFunc) (string, error) {
+	result, err := exec("LANDMARKS\n", "END_LANDMARKS", 2*time.Second)
+	if err != nil {
+		return "", err
 	}
 
 	// Parse landmarks
@@ -81,112 +72,88 @@ func ParseLandmarks(data string) string {
 	return strings.Join(result, " | ")
 }
 
-func ReadMemory(portName, address, length string) (string, error) {
-	mode := &goserial.Mode{
-		BaudRate: 115200,
-	}
-
-	port, err := goserial.Open(portName, mode)
-	if err != nil {
-		return "", fmt.Errorf("failed to open port: %w", err)
+// LandmarkAddress finds a landmark, e.g. "main", in the text returned by ParseLandmarks
+func LandmarkAddress(landmarks, name string) (uint32, bool) {
+	for _, landmark := range strings.Split(landmarks, " | ") {
+		landmarkName, addr, ok := strings.Cut(landmark, " @ ")
+		if !ok || landmarkName != name {
+			continue
+		}
+		val, err := strconv.ParseUint(addr, 0, 32)
+		if err != nil {
+			return 0, false
+		}
+		return uint32(val), true
 	}
-	defer port.Close()
-
-	// Clear any buffered data before sending command
-	time.Sleep(100 * time.Millisecond)
-	clearBuf := make([]byte, 4096)
-	port.SetReadTimeout(50 * time.Millisecond)
-	port.Read(clearBuf) // Discard any leftover data
+	return 0, false
+}
 
-	// Send command
+func (s *Session) ReadMemory(address, length string) (string, error) {
 	command := fmt.Sprintf("READ:%s:%s\n", address, length)
-	_, err = port.Write([]byte(command))
+	return s.Exec(command, "===END===", 5*time.Second)
+}
+
+// ReadBlock reads memory and returns the raw bytes instead of the hex dump
+func (s *Session) ReadBlock(address uint32, length int) (format.MemoryBlock, error) {
+	result, err := s.ReadMemory(fmt.Sprintf("0x%08x", address), strconv.Itoa(length))
 	if err != nil {
-		return "", fmt.Errorf("failed to write: %w", err)
+		return format.MemoryBlock{}, err
+	}
+	if idx := strings.Index(result, "ERROR:"); idx != -1 {
+		line, _, _ := strings.Cut(result[idx:], "\n")
+		return format.MemoryBlock{}, fmt.Errorf("%s", strings.TrimSpace(line))
 	}
+	return format.MemoryBlock{Address: address, Data: format.ParseHexDump(result)}, nil
+}
 
-	// Read until we see ===END===
-	result := ""
-	buf := make([]byte, 1024)
-	startTime := time.Now()
+// MaxReadSize matches the firmware's default PICOPEEKER_MAX_READ_SIZE
+const MaxReadSize = 4096
 
-	for {
-		// Timeout after 5 seconds
-		if time.Since(startTime) > 5*time.Second {
-			if result == "" {
-				return "", fmt.Errorf("timeout: no response from Pico")
-			}
-			return result, nil
-		}
+// ReadRegion reads a range larger than a single READ allows, in MaxReadSize
+// chunks. progress is called after each chunk; closing stop ends the read early
+// and returns what was read so far along with ErrStopped.
+func (s *Session) ReadRegion(address uint32, length int, progress func(done, total int), stop <-chan struct{}) (format.MemoryBlock, error) {
+	block := format.MemoryBlock{Address: address, Data: make([]byte, 0, length)}
 
-		port.SetReadTimeout(100 * time.Millisecond)
-		n, _ := port.Read(buf)
+	for offset := 0; offset < length; offset += MaxReadSize {
+		select {
+		case <-stop:
+			return block, ErrStopped
+		default:
+		}
 
-		if n > 0 {
-			result += string(buf[:n])
+		n := min(MaxReadSize, length-offset)
+		chunk, err := s.ReadBlock(address+uint32(offset), n)
+		if err != nil {
+			return block, fmt.Errorf("read at 0x%08x failed: %w", address+uint32(offset), err)
+		}
+		block.Data = append(block.Data, chunk.Data...)
 
-			// Check if we got the end marker
-			if strings.Contains(result, "===END===") {
-				return result, nil
-			}
+		if progress != nil {
+			progress(offset+n, length)
 		}
 	}
+	return block, nil
 }
 
-func SearchMemory(portName, pattern string) (string, error) {
-	mode := &goserial.Mode{
-		BaudRate: 115200,
-	}
+// ErrStopped is returned when a long operation is stopped by the user
+var ErrStopped = errors.New("stopped")
 
-	port, err := goserial.Open(portName, mode)
-	if err != nil {
-		return "", fmt.Errorf("failed to open port: %w", err)
-	}
-	defer port.Close()
-
-	// Clear any buffered data before sending command
-	time.Sleep(100 * time.Millisecond)
-	clearBuf := make([]byte, 4096)
-	port.SetReadTimeout(50 * time.Millisecond)
-	port.Read(clearBuf) // Discard any leftover data
-
-	// Send command
+func (s *Session) SearchMemory(pattern string) (string, error) {
+	// Search takes longer, so we use a longer timeout (5-10 seconds is typical)
 	command := fmt.Sprintf("SEARCH:%s\n", pattern)
-	_, err = port.Write([]byte(command))
-	if err != nil {
-		return "", fmt.Errorf("failed to write: %w", err)
-	}
-
-	// Read until we see ===END===
-	// Search takes longer, so we use a longer timeout
-	result := ""
-	buf := make([]byte, 1024)
-	startTime := time.Now()
-
-	for {
-		// Timeout after 30 seconds (search can be slow)
-		if time.Since(startTime) > 30*time.Second {
-			if result == "" {
-				return "", fmt.Errorf("timeout: no response from Pico")
-			}
-			return result, nil
-		}
-
-		port.SetReadTimeout(100 * time.Millisecond)
-		n, _ := port.Read(buf)
-
-		if n > 0 {
-			result += string(buf[:n])
+	return s.Exec(command, "===END===", 30*time.Second)
+}
 
-			// Check if we got the end marker
-			if strings.Contains(result, "===END===") {
-				return result, nil
-			}
-		}
-	}
+func (s *Session) SearchFlash(pattern string) (string, error) {
+	// Flash search can take a LONG time (4MB), so use a very long timeout
+	command := fmt.Sprintf("SEARCHFLASH:%s\n", pattern)
+	return s.Exec(command, "===END===", 120*time.Second)
 }
 
-func SearchFlash(portName, pattern string) (string, error) {
+// runCommand opens the port, sends a single command and collects the response
+// until endMarker is seen or the timeout expires
+func runCommand(portName, command, endMarker string, timeout time.Duration) (string, error) {
 	mode := &goserial.Mode{
 		BaudRate: 115200,
 	}
@@ -203,22 +170,19 @@ func SearchFlash(portName, pattern string) (string, error) {
 	port.SetReadTimeout(50 * time.Millisecond)
 	port.Read(clearBuf) // Discard any leftover data
 
-	// Send SEARCHFLASH command
-	command := fmt.Sprintf("SEARCHFLASH:%s\n", pattern)
+	// Send command
 	_, err = port.Write([]byte(command))
 	if err != nil {
 		return "", fmt.Errorf("failed to write: %w", err)
 	}
 
-	// Read until we see ===END===
-	// Flash search can take a LONG time (4MB), so use a very long timeout
+	// Read until we see the end marker
 	result := ""
 	buf := make([]byte, 1024)
 	startTime := time.Now()
 
 	for {
-		// Timeout after 120 seconds (Flash is much larger than SRAM)
-		if time.Since(startTime) > 120*time.Second {
+		if time.Since(startTime) > timeout {
 			if result == "" {
 				return "", fmt.Errorf("timeout: no response from Pico")
 			}
@@ -232,7 +196,7 @@ func SearchFlash(portName, pattern string) (string, error) {
 			result += string(buf[:n])
 
 			// Check if we got the end marker
-			if strings.Contains(result, "===END===") {
+			if strings.Contains(result, endMarker) {
 				return result, nil
 			}
 		}
@@ -0,0 +1,363 @@
+package serial
+
+import (
+	"github.com/MironCo/picopeeker/internal/util"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	goserial "go.bug.st/serial"
+)
+
+// ConnState is the health of a Session's connection
+type ConnState int
+
+const (
+	Disconnected ConnState = iota
+	Connected
+	Reconnecting
+)
+
+// String returns the display name of the state
+func (c ConnState) String() string {
+	switch c {
+	case Connected:
+		return "Connected"
+	case Reconnecting:
+		return "Reconnecting"
+	default:
+		return "Disconnected"
+	}
+}
+
+const (
+	healthCheckInterval = 2 * time.Second
+	minReconnectDelay   = 250 * time.Millisecond
+	maxReconnectDelay   = 5 * time.Second
+)
+
+// Session keeps a serial port open across commands. It watches the connection,
+// and when the Pico resets or is unplugged it backs off and reconnects, following
+// the board by USB serial number if it comes back under a different port name.
+type Session struct {
+	mu           sync.Mutex // Held for the whole of a command
+	port         goserial.Port
+	openName     string // Name the current port was opened with
+	reconnecting bool
+
+	stateMu       sync.Mutex // Guards the fields below, never held during I/O
+	portName      string
+	serialNumber  string // USB serial number of the board, learned on first connect
+	state         ConnState
+	onStateChange func(state ConnState, portName string)
+	onReconnect   []func()
+
+	recMu     sync.Mutex
+	recording *Recording // Non-nil while recording
+	replay    *replayer  // Non-nil for sessions replaying a recording
+
+	done chan struct{}
+}
+
+// NewSession creates a session for the port and starts its health monitor.
+// The port is opened lazily on the first command.
+func NewSession(portName string) *Session {
+	s := &Session{
+		portName: portName,
+		done:     make(chan struct{}),
+	}
+	go s.monitor()
+	return s
+}
+
+// Port returns the current port name
+func (s *Session) Port() string {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	return s.portName
+}
+
+// State returns the current connection state
+func (s *Session) State() ConnState {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	return s.state
+}
+
+// SetPort switches the session to a different port, e.g. when the user edits it.
+// The old port is closed before the next command.
+func (s *Session) SetPort(portName string) {
+	if s.replay != nil {
+		return // Replays aren't bound to a port
+	}
+
+	s.stateMu.Lock()
+	if portName == s.portName {
+		s.stateMu.Unlock()
+		return
+	}
+	s.portName = portName
+	s.serialNumber = ""
+	s.stateMu.Unlock()
+
+	s.setState(Disconnected)
+}
+
+// OnStateChange registers a callback for connection state changes. It may be
+// called from a background goroutine.
+func (s *Session) OnStateChange(fn func(state ConnState, portName string)) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	s.onStateChange = fn
+}
+
+// OnReconnect registers a callback run (from a background goroutine) after the
+// connection is restored, so callers can replay anything the reset lost
+func (s *Session) OnReconnect(fn func()) {
+	s.stateMu.Lock()
+	defer s.stateMu.Unlock()
+	s.onReconnect = append(s.onReconnect, fn)
+}
+
+// Close stops the health monitor and any reconnect attempts, and closes the port
+// once the running command (if any) finishes
+func (s *Session) Close() {
+	s.stateMu.Lock()
+	if s.closed() {
+		s.stateMu.Unlock()
+		return
+	}
+	close(s.done)
+	s.stateMu.Unlock()
+
+	go func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.closePort()
+	}()
+}
+
+// closed reports whether Close has been called
+func (s *Session) closed() bool {
+	select {
+	case <-s.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Exec sends a single command and collects the response until endMarker is seen
+// or the timeout expires
+func (s *Session) Exec(command, endMarker string, timeout time.Duration) (string, error) {
+	if s.replay != nil {
+		return s.replay.exec(command)
+	}
+
+	start := time.Now()
+	result, err := s.execPort(command, endMarker, timeout)
+	s.record(start, command, result, err)
+	return result, err
+}
+
+// execPort runs the command on the serial port
+func (s *Session) execPort(command, endMarker string, timeout time.Duration) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureOpen(); err != nil {
+		return "", err
+	}
+
+	// Clear any buffered data before sending command
+	clearBuf := make([]byte, 4096)
+	s.port.SetReadTimeout(50 * time.Millisecond)
+	if _, err := s.port.Read(clearBuf); err != nil {
+		return "", s.lostConnection(err)
+	}
+
+	// Send command
+	if _, err := s.port.Write([]byte(command)); err != nil {
+		return "", s.lostConnection(err)
+	}
+
+	// Read until we see the end marker
+	result := ""
+	buf := make([]byte, 1024)
+	startTime := time.Now()
+
+	for {
+		if time.Since(startTime) > timeout {
+			if result == "" {
+				return "", fmt.Errorf("timeout: no response from Pico")
+			}
+			return result, nil
+		}
+
+		s.port.SetReadTimeout(100 * time.Millisecond)
+		n, err := s.port.Read(buf)
+		if err != nil {
+			return "", s.lostConnection(err)
+		}
+
+		if n > 0 {
+			result += string(buf[:n])
+
+			// Check if we got the end marker
+			if strings.Contains(result, endMarker) {
+				return result, nil
+			}
+		}
+	}
+}
+
+// ensureOpen opens the port if needed. Must be called with s.mu held.
+func (s *Session) ensureOpen() error {
+	portName := s.Port()
+	if s.port != nil && s.openName == portName {
+		return nil
+	}
+	s.closePort()
+	if s.closed() {
+		return fmt.Errorf("session closed")
+	}
+	if portName == "" {
+		return fmt.Errorf("no serial port selected")
+	}
+
+	mode := &goserial.Mode{
+		BaudRate: 115200,
+	}
+
+	port, err := goserial.Open(portName, mode)
+	if err != nil {
+		if !s.reconnecting {
+			s.reconnecting = true
+			go s.reconnect()
+		}
+		return fmt.Errorf("failed to open port: %w", err)
+	}
+	s.port = port
+	s.openName = portName
+
+	// Remember the board so it can be found again if it re-enumerates
+	s.stateMu.Lock()
+	if s.serialNumber == "" {
+		if ports, err := util.FindPicoPorts(); err == nil {
+			for _, info := range ports {
+				if info.Name == portName {
+					s.serialNumber = info.SerialNumber
+				}
+			}
+		}
+	}
+	s.stateMu.Unlock()
+
+	s.setState(Connected)
+	return nil
+}
+
+// lostConnection closes the port after an I/O error and starts reconnecting.
+// Must be called with s.mu held.
+func (s *Session) lostConnection(err error) error {
+	s.closePort()
+	if !s.reconnecting {
+		s.reconnecting = true
+		go s.reconnect()
+	}
+	return fmt.Errorf("connection lost: %w", err)
+}
+
+// closePort closes the port if open. Must be called with s.mu held.
+func (s *Session) closePort() {
+	if s.port != nil {
+		s.port.Close()
+		s.port = nil
+		s.openName = ""
+	}
+}
+
+// reconnect retries opening the port with exponential backoff until it succeeds
+// or the session is closed
+func (s *Session) reconnect() {
+	s.setState(Reconnecting)
+	delay := minReconnectDelay
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-time.After(delay):
+		}
+
+		s.mu.Lock()
+		// The CDC device may come back under a new name (e.g. ttyACM0 -> ttyACM1)
+		s.stateMu.Lock()
+		if info, ok := util.FindPortBySerial(s.serialNumber); ok {
+			s.portName = info.Name
+		}
+		s.stateMu.Unlock()
+
+		err := s.ensureOpen()
+		if err == nil {
+			s.reconnecting = false
+			s.mu.Unlock()
+
+			s.stateMu.Lock()
+			hooks := append([]func(){}, s.onReconnect...)
+			s.stateMu.Unlock()
+			for _, hook := range hooks {
+				hook()
+			}
+			return
+		}
+		s.mu.Unlock()
+
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+// monitor periodically checks that an idle port is still attached
+func (s *Session) monitor() {
+	ticker := time.NewTicker(healthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-ticker.C:
+		}
+
+		// Skip the check while a command is running, it will notice errors itself
+		if !s.mu.TryLock() {
+			continue
+		}
+		if s.port != nil {
+			if s.openName != s.Port() {
+				s.closePort() // Port was switched while idle
+			} else if _, err := s.port.GetModemStatusBits(); err != nil {
+				s.lostConnection(err)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
+
+// setState records the new state and notifies the listener if it changed
+func (s *Session) setState(state ConnState) {
+	s.stateMu.Lock()
+	changed := s.state != state
+	s.state = state
+	portName := s.portName
+	fn := s.onStateChange
+	s.stateMu.Unlock()
+
+	if changed && fn != nil {
+		fn(state, portName)
+	}
+}
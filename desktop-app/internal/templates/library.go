@@ -0,0 +1,75 @@
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// DefaultSource is written to a new library as an example
+const DefaultSource = `// Struct templates for the Read tab. Layouts are packed: use padding
+// fields or "@ offset" to match how the compiler laid the struct out.
+// Types: uint8..uint64, int8..int64 (or u8/i8... and the _t names),
+// float, double, char, bool, ptr, be16/be32/be64, or another struct.
+// Prefix a field with "be" for big-endian, e.g. "be uint32 id;".
+
+struct Point {
+    int16 x;
+    int16 y;
+};
+
+struct Sprite {
+    Point  pos;
+    uint8  flags;
+    uint8  frame;
+    uint16 tile;
+    char   name[8];
+};
+`
+
+// LibraryPath returns where the template library is stored
+func LibraryPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("no config directory: %w", err)
+	}
+	return filepath.Join(dir, "picopeeker", "templates.txt"), nil
+}
+
+// LoadSource reads the template library, or returns DefaultSource if none has been saved yet
+func LoadSource() (string, error) {
+	path, err := LibraryPath()
+	if err != nil {
+		return DefaultSource, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return DefaultSource, nil
+	}
+	if err != nil {
+		return DefaultSource, fmt.Errorf("failed to read template library: %w", err)
+	}
+	return string(data), nil
+}
+
+// SaveSource checks that the source parses and writes it to the template library
+func SaveSource(source string) (*Library, error) {
+	lib, err := Parse(source)
+	if err != nil {
+		return nil, err
+	}
+
+	path, err := LibraryPath()
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, []byte(source), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to save template library: %w", err)
+	}
+	return lib, nil
+}
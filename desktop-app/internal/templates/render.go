@@ -0,0 +1,183 @@
+package templates
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// MaxArrayRows limits how many elements of an array are listed, including the
+// copies of a struct Render overlays
+const MaxArrayRows = 64
+
+// row is one line of a rendered field table
+type row struct {
+	offset int
+	name   string
+	typ    string
+	value  string
+}
+
+// Render overlays count consecutive copies of the struct on data read from
+// addr and returns a field table. Fields past the end of data are marked as not read.
+func Render(s *Struct, data []byte, addr uint32, count int) string {
+	if count < 1 {
+		count = 1
+	}
+
+	var rows []row
+	for i := 0; i < count; i++ {
+		if i == MaxArrayRows {
+			rows = append(rows, row{i * s.Size, fmt.Sprintf("[%d..%d]", i, count-1), s.Name, "..."})
+			break
+		}
+		prefix := ""
+		if count > 1 {
+			prefix = fmt.Sprintf("[%d].", i)
+		}
+		rows = appendStructRows(rows, s, data, i*s.Size, prefix)
+	}
+
+	var sb strings.Builder
+	title := s.Name
+	if count > 1 {
+		title = fmt.Sprintf("%s[%d]", s.Name, count)
+	}
+	sb.WriteString(fmt.Sprintf("=== %s at 0x%08x (%d bytes) ===\n\n", title, addr, s.Size*count))
+
+	nameWidth, typeWidth := len("Field"), len("Type")
+	for _, r := range rows {
+		nameWidth = max(nameWidth, len(r.name))
+		typeWidth = max(typeWidth, len(r.typ))
+	}
+	sb.WriteString(fmt.Sprintf("Address:  Offset  %-*s  %-*s  Value\n", nameWidth, "Field", typeWidth, "Type"))
+	sb.WriteString(fmt.Sprintf("--------  ------  %s  %s  -----\n", strings.Repeat("-", nameWidth), strings.Repeat("-", typeWidth)))
+	for _, r := range rows {
+		sb.WriteString(fmt.Sprintf("%08x: +0x%04x %-*s  %-*s  %s\n", addr+uint32(r.offset), r.offset, nameWidth, r.name, typeWidth, r.typ, r.value))
+	}
+
+	sb.WriteString("\n===END===\n")
+	return sb.String()
+}
+
+func appendStructRows(rows []row, s *Struct, data []byte, base int, prefix string) []row {
+	for _, field := range s.Fields {
+		offset := base + field.Offset
+		name := prefix + field.Name
+
+		switch {
+		case field.Struct != nil && field.Count == 0:
+			rows = appendStructRows(rows, field.Struct, data, offset, name+".")
+		case field.Struct != nil:
+			for i := 0; i < field.Count; i++ {
+				if i == MaxArrayRows {
+					rows = append(rows, row{offset + i*field.Struct.Size, fmt.Sprintf("%s[%d..%d]", name, i, field.Count-1), field.Type, "..."})
+					break
+				}
+				rows = appendStructRows(rows, field.Struct, data, offset+i*field.Struct.Size, fmt.Sprintf("%s[%d].", name, i))
+			}
+		case field.prim.kind == KindChar && field.Count > 0:
+			// Character arrays are shown as one string
+			typ := fmt.Sprintf("char[%d]", field.Count)
+			rows = append(rows, row{offset, name, typ, formatString(slice(data, offset, field.Count))})
+		case field.Count > 0:
+			for i := 0; i < field.Count; i++ {
+				if i == MaxArrayRows {
+					rows = append(rows, row{offset + i*field.prim.size, fmt.Sprintf("%s[%d..%d]", name, i, field.Count-1), field.Type, "..."})
+					break
+				}
+				elemOffset := offset + i*field.prim.size
+				rows = append(rows, row{elemOffset, fmt.Sprintf("%s[%d]", name, i), typeName(field), formatValue(field.prim, slice(data, elemOffset, field.prim.size))})
+			}
+		default:
+			rows = append(rows, row{offset, name, typeName(field), formatValue(field.prim, slice(data, offset, field.prim.size))})
+		}
+	}
+	return rows
+}
+
+// slice returns data[offset:offset+n], or nil if it wasn't read
+func slice(data []byte, offset, n int) []byte {
+	if offset < 0 || offset+n > len(data) {
+		return nil
+	}
+	return data[offset : offset+n]
+}
+
+func typeName(field *Field) string {
+	if field.BigEndian && !strings.HasPrefix(field.Type, "be") {
+		return "be " + field.Type
+	}
+	return field.Type
+}
+
+func formatValue(prim *primitive, data []byte) string {
+	if data == nil {
+		return "(not read)"
+	}
+
+	var order binary.ByteOrder = binary.LittleEndian
+	if prim.bigEndian {
+		order = binary.BigEndian
+	}
+
+	var raw uint64
+	switch prim.size {
+	case 1:
+		raw = uint64(data[0])
+	case 2:
+		raw = uint64(order.Uint16(data))
+	case 4:
+		raw = uint64(order.Uint32(data))
+	case 8:
+		raw = order.Uint64(data)
+	}
+
+	switch prim.kind {
+	case KindInt:
+		shift := 64 - 8*prim.size
+		return fmt.Sprintf("%d", int64(raw<<shift)>>shift)
+	case KindFloat:
+		if prim.size == 4 {
+			return fmt.Sprintf("%g", math.Float32frombits(uint32(raw)))
+		}
+		return fmt.Sprintf("%g", math.Float64frombits(raw))
+	case KindChar:
+		if raw >= 0x20 && raw < 0x7F {
+			return fmt.Sprintf("'%c' (%d)", rune(raw), raw)
+		}
+		return fmt.Sprintf("'\\x%02x' (%d)", raw, raw)
+	case KindBool:
+		if raw == 0 {
+			return "false"
+		}
+		return fmt.Sprintf("true (%d)", raw)
+	case KindPointer:
+		return fmt.Sprintf("0x%08x", raw)
+	default:
+		return fmt.Sprintf("%d (0x%0*x)", raw, prim.size*2, raw)
+	}
+}
+
+// formatString shows a char array up to its NUL terminator, with escapes for
+// anything unprintable
+func formatString(data []byte) string {
+	if data == nil {
+		return "(not read)"
+	}
+	var sb strings.Builder
+	sb.WriteString("\"")
+	for _, b := range data {
+		if b == 0 {
+			break
+		}
+		if b >= 0x20 && b < 0x7F && b != '"' && b != '\\' {
+			sb.WriteByte(b)
+		} else {
+			sb.WriteString(fmt.Sprintf("\\x%02x", b))
+		}
+	}
+	sb.WriteString("\"")
+	return sb.String()
+}
@@ -0,0 +1,372 @@
+package templates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Templates are written in a small C-like schema:
+//
+//	struct Sprite {
+//	    uint16 x;
+//	    uint16 y;
+//	    uint8  flags;
+//	    char   name[8];
+//	    be32   id;              // Big-endian
+//	    be uint16 checksum;     // Same, with an explicit prefix
+//	    uint32 next @ 0x18;     // Explicit offset from the start of the struct
+//	    Point  pos;             // Nested struct, defined anywhere in the library
+//	};
+//
+// Layouts are packed: each field follows the previous one unless it has an
+// explicit @offset, so padding must be written out (or skipped with @).
+
+// Kind is how a primitive's bytes are interpreted
+type Kind int
+
+const (
+	KindUint Kind = iota
+	KindInt
+	KindFloat
+	KindChar
+	KindBool
+	KindPointer
+)
+
+type primitive struct {
+	size      int
+	kind      Kind
+	bigEndian bool
+}
+
+var primitives = map[string]primitive{
+	"uint8": {1, KindUint, false}, "u8": {1, KindUint, false}, "uint8_t": {1, KindUint, false}, "byte": {1, KindUint, false},
+	"int8": {1, KindInt, false}, "i8": {1, KindInt, false}, "int8_t": {1, KindInt, false},
+	"uint16": {2, KindUint, false}, "u16": {2, KindUint, false}, "uint16_t": {2, KindUint, false},
+	"int16": {2, KindInt, false}, "i16": {2, KindInt, false}, "int16_t": {2, KindInt, false},
+	"uint32": {4, KindUint, false}, "u32": {4, KindUint, false}, "uint32_t": {4, KindUint, false}, "unsigned": {4, KindUint, false},
+	"int32": {4, KindInt, false}, "i32": {4, KindInt, false}, "int32_t": {4, KindInt, false}, "int": {4, KindInt, false},
+	"uint64": {8, KindUint, false}, "u64": {8, KindUint, false}, "uint64_t": {8, KindUint, false},
+	"int64": {8, KindInt, false}, "i64": {8, KindInt, false}, "int64_t": {8, KindInt, false},
+	"float": {4, KindFloat, false}, "f32": {4, KindFloat, false},
+	"double": {8, KindFloat, false}, "f64": {8, KindFloat, false},
+	"char": {1, KindChar, false}, "bool": {1, KindBool, false},
+	"ptr": {4, KindPointer, false},
+	// Big-endian shorthands
+	"be16": {2, KindUint, true}, "be32": {4, KindUint, true}, "be64": {8, KindUint, true},
+}
+
+// Field is one member of a struct
+type Field struct {
+	Name      string
+	Type      string // Primitive or struct name, as written
+	Offset    int    // Bytes from the start of the struct
+	Count     int    // Array length, 0 for a single value
+	BigEndian bool
+
+	prim   *primitive
+	Struct *Struct // Set for nested structs
+}
+
+// ElemSize returns the size of one element of the field
+func (f *Field) ElemSize() int {
+	if f.Struct != nil {
+		return f.Struct.Size
+	}
+	return f.prim.size
+}
+
+// Size returns the total size of the field, including all array elements
+func (f *Field) Size() int {
+	if f.Count > 0 {
+		return f.ElemSize() * f.Count
+	}
+	return f.ElemSize()
+}
+
+// Struct is a parsed struct template
+type Struct struct {
+	Name   string
+	Fields []*Field
+	Size   int
+}
+
+// Library is a set of struct templates parsed from one schema source
+type Library struct {
+	Structs []*Struct // In the order they were defined
+	byName  map[string]*Struct
+}
+
+// Find returns the struct with the given name
+func (l *Library) Find(name string) (*Struct, bool) {
+	s, ok := l.byName[name]
+	return s, ok
+}
+
+// Names returns the struct names in definition order
+func (l *Library) Names() []string {
+	names := make([]string, len(l.Structs))
+	for i, s := range l.Structs {
+		names[i] = s.Name
+	}
+	return names
+}
+
+// token is a word, number or punctuation character with its line for errors
+type token struct {
+	text string
+	line int
+}
+
+func tokenize(source string) []token {
+	var tokens []token
+	line := 1
+	runes := []rune(source)
+
+	for i := 0; i < len(runes); {
+		c := runes[i]
+		switch {
+		case c == '\n':
+			line++
+			i++
+		case unicode.IsSpace(c):
+			i++
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '/':
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+		case c == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			i += 2
+			for i+1 < len(runes) && !(runes[i] == '*' && runes[i+1] == '/') {
+				if runes[i] == '\n' {
+					line++
+				}
+				i++
+			}
+			i += 2
+		case c == '_' || unicode.IsLetter(c) || unicode.IsDigit(c):
+			start := i
+			for i < len(runes) && (runes[i] == '_' || unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i])) {
+				i++
+			}
+			tokens = append(tokens, token{string(runes[start:i]), line})
+		default:
+			tokens = append(tokens, token{string(c), line})
+			i++
+		}
+	}
+	return tokens
+}
+
+// parser walks the token list
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].text
+	}
+	return ""
+}
+
+func (p *parser) line() int {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos].line
+	}
+	if len(p.tokens) > 0 {
+		return p.tokens[len(p.tokens)-1].line
+	}
+	return 1
+}
+
+func (p *parser) next() string {
+	text := p.peek()
+	p.pos++
+	return text
+}
+
+func (p *parser) expect(text string) error {
+	line := p.line()
+	if got := p.next(); got != text {
+		return fmt.Errorf("line %d: expected %q, got %q", line, text, got)
+	}
+	return nil
+}
+
+func (p *parser) identifier(what string) (string, error) {
+	line := p.line()
+	name := p.next()
+	if name == "" || !(name[0] == '_' || unicode.IsLetter(rune(name[0]))) {
+		return "", fmt.Errorf("line %d: expected %s, got %q", line, what, name)
+	}
+	return name, nil
+}
+
+func (p *parser) number(what string) (int, error) {
+	line := p.line()
+	text := p.next()
+	val, err := strconv.ParseUint(text, 0, 32)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: invalid %s %q", line, what, text)
+	}
+	return int(val), nil
+}
+
+// Parse reads a schema source containing any number of struct definitions
+func Parse(source string) (*Library, error) {
+	p := &parser{tokens: tokenize(source)}
+	lib := &Library{byName: make(map[string]*Struct)}
+
+	for p.peek() != "" {
+		s, err := p.parseStruct()
+		if err != nil {
+			return nil, err
+		}
+		if _, exists := lib.byName[s.Name]; exists {
+			return nil, fmt.Errorf("struct %s is defined twice", s.Name)
+		}
+		lib.Structs = append(lib.Structs, s)
+		lib.byName[s.Name] = s
+	}
+
+	// Nested structs may be defined later in the source, so resolve them last
+	for _, s := range lib.Structs {
+		if err := lib.resolve(s, nil); err != nil {
+			return nil, err
+		}
+	}
+	return lib, nil
+}
+
+func (p *parser) parseStruct() (*Struct, error) {
+	if err := p.expect("struct"); err != nil {
+		return nil, err
+	}
+	name, err := p.identifier("struct name")
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+
+	s := &Struct{Name: name, Size: -1}
+	seen := make(map[string]bool)
+	for p.peek() != "}" {
+		if p.peek() == "" {
+			return nil, fmt.Errorf("struct %s: missing closing }", name)
+		}
+		field, err := p.parseField()
+		if err != nil {
+			return nil, fmt.Errorf("struct %s: %w", name, err)
+		}
+		if seen[field.Name] {
+			return nil, fmt.Errorf("struct %s: field %s is defined twice", name, field.Name)
+		}
+		seen[field.Name] = true
+		s.Fields = append(s.Fields, field)
+	}
+	p.next() // }
+	if p.peek() == ";" {
+		p.next()
+	}
+	return s, nil
+}
+
+// Limits that keep a mistyped length or offset from asking the device for
+// gigabytes: a struct is read in one go, so it has to fit in a sensible READ
+const (
+	maxArrayLength = 0x10000
+	maxStructSize  = 0x100000
+)
+
+func (p *parser) parseField() (*Field, error) {
+	field := &Field{Offset: -1}
+
+	// Optional byte order prefix
+	switch strings.ToLower(p.peek()) {
+	case "be":
+		field.BigEndian = true
+		p.next()
+	case "le":
+		p.next()
+	}
+
+	var err error
+	if field.Type, err = p.identifier("type"); err != nil {
+		return nil, err
+	}
+	if field.Name, err = p.identifier("field name"); err != nil {
+		return nil, err
+	}
+
+	if p.peek() == "[" {
+		p.next()
+		if field.Count, err = p.number("array length"); err != nil {
+			return nil, err
+		}
+		if field.Count == 0 {
+			return nil, fmt.Errorf("line %d: array %s has no elements", p.line(), field.Name)
+		}
+		if field.Count > maxArrayLength {
+			return nil, fmt.Errorf("line %d: array %s has more than %d elements", p.line(), field.Name, maxArrayLength)
+		}
+		if err := p.expect("]"); err != nil {
+			return nil, err
+		}
+	}
+
+	if p.peek() == "@" {
+		p.next()
+		if field.Offset, err = p.number("offset"); err != nil {
+			return nil, err
+		}
+	}
+
+	return field, p.expect(";")
+}
+
+// resolve looks up field types and lays out the struct. visiting catches
+// structs that contain themselves.
+func (l *Library) resolve(s *Struct, visiting []string) error {
+	if s.Size >= 0 {
+		return nil
+	}
+	for _, name := range visiting {
+		if name == s.Name {
+			return fmt.Errorf("struct %s contains itself", s.Name)
+		}
+	}
+	visiting = append(visiting, s.Name)
+
+	offset, size := 0, 0
+	for _, field := range s.Fields {
+		if prim, ok := primitives[field.Type]; ok {
+			prim.bigEndian = prim.bigEndian || field.BigEndian
+			field.prim = &prim
+			field.BigEndian = prim.bigEndian
+		} else if nested, ok := l.byName[field.Type]; ok {
+			if err := l.resolve(nested, visiting); err != nil {
+				return err
+			}
+			field.Struct = nested
+		} else {
+			return fmt.Errorf("struct %s: unknown type %q for field %s", s.Name, field.Type, field.Name)
+		}
+
+		if field.Offset < 0 {
+			field.Offset = offset
+		}
+		offset = field.Offset + field.Size()
+		size = max(size, offset)
+		if size > maxStructSize {
+			return fmt.Errorf("struct %s is larger than %d bytes", s.Name, maxStructSize)
+		}
+	}
+	s.Size = size
+	return nil
+}
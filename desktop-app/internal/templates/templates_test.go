@@ -0,0 +1,58 @@
+package templates
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseLimits(t *testing.T) {
+	tests := []struct {
+		source  string
+		wantErr string
+	}{
+		{"struct A { uint8 data[65536]; };", ""},
+		{"struct A { uint8 data[65537]; };", "has more than 65536 elements"},
+		{"struct A { uint8 data[0xFFFFFFFF]; };", "has more than 65536 elements"},
+		{"struct A { uint8 last @ 0xFFFFFFF0; };", "larger than 1048576 bytes"},
+		{"struct A { uint8 data[4096]; }; struct B { A items[512]; };", "struct B is larger than"},
+	}
+
+	for _, tt := range tests {
+		_, err := Parse(tt.source)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("Parse(%q): %v", tt.source, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Parse(%q): err = %v, want %q", tt.source, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRenderArrayRows(t *testing.T) {
+	lib, err := Parse("struct Point { uint8 x; uint8 y; }; struct Path { Point points[100]; uint8 ids[100]; };")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path, _ := lib.Find("Path")
+	point, _ := lib.Find("Point")
+
+	out := Render(path, make([]byte, path.Size), 0x20000000, 1)
+	for _, want := range []string{"points[63].y", "points[64..99]", "ids[63]", "ids[64..99]"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Render(Path) has no %q row", want)
+		}
+	}
+	for _, unwanted := range []string{"points[64].x", "ids[64] "} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("Render(Path) lists %q past the row limit", unwanted)
+		}
+	}
+
+	out = Render(point, make([]byte, point.Size*100), 0x20000000, 100)
+	if !strings.Contains(out, "[63].y") || !strings.Contains(out, "[64..99]") || strings.Contains(out, "[64].x") {
+		t.Errorf("Render(Point, 100 copies) isn't capped at %d:\n%s", MaxArrayRows, out)
+	}
+}
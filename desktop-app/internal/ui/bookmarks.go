@@ -0,0 +1,281 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/bookmarks"
+	"fmt"
+	"image/color"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// BookmarkPanel is the sidebar listing the named address ranges of the
+// current firmware project. Projects are saved as they change, so bookmarks
+// survive restarts.
+type BookmarkPanel struct {
+	ctx     *DeviceContext
+	project *bookmarks.Project
+
+	selected  int // -1 if none
+	title     *widget.Label
+	list      *widget.List
+	editBtn   *widget.Button
+	deleteBtn *widget.Button
+	content   fyne.CanvasObject
+}
+
+// NewBookmarkPanel creates the sidebar with an empty default project
+func NewBookmarkPanel(ctx *DeviceContext) *BookmarkPanel {
+	p := &BookmarkPanel{
+		ctx:      ctx,
+		project:  &bookmarks.Project{Key: bookmarks.DefaultKey, Name: "No firmware identified"},
+		selected: -1,
+	}
+
+	p.title = widget.NewLabel("")
+	p.title.Truncation = fyne.TextTruncateEllipsis
+
+	p.list = widget.NewList(
+		func() int { return len(p.project.Bookmarks) },
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("")
+			name.TextStyle = fyne.TextStyle{Bold: true}
+			name.Truncation = fyne.TextTruncateEllipsis
+			detail := widget.NewLabel("")
+			detail.TextStyle = fyne.TextStyle{Monospace: true}
+			detail.Truncation = fyne.TextTruncateEllipsis
+			comment := widget.NewLabel("")
+			comment.Truncation = fyne.TextTruncateEllipsis
+			return container.NewVBox(name, detail, comment)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			b := p.project.Bookmarks[id]
+			labels := item.(*fyne.Container).Objects
+			labels[0].(*widget.Label).SetText(b.Name)
+			detail := fmt.Sprintf("0x%08x  %d byte(s)", b.Address, b.Length)
+			if b.Type != "" {
+				detail += "  " + b.Type
+			}
+			labels[1].(*widget.Label).SetText(detail)
+			labels[2].(*widget.Label).SetText(b.Comment)
+		},
+	)
+	p.list.OnSelected = func(id widget.ListItemID) {
+		p.selected = id
+		p.editBtn.Enable()
+		p.deleteBtn.Enable()
+		b := p.project.Bookmarks[id]
+		ctx.HexView.Select(b.Address, b.Length)
+	}
+	p.list.OnUnselected = func(widget.ListItemID) {
+		p.selected = -1
+		p.editBtn.Disable()
+		p.deleteBtn.Disable()
+	}
+
+	// New bookmarks start from the hex viewer's selection
+	addBtn := widget.NewButton("Add...", func() {
+		b := bookmarks.Bookmark{Length: 1, Type: "data"}
+		if start, length, ok := ctx.HexView.Selection(); ok {
+			b.Address, b.Length = start, length
+			b.Name = ctx.Symbols().Name(start)
+		}
+		p.showEditor("Add Bookmark", b, func(b bookmarks.Bookmark) {
+			p.project.Add(b)
+		})
+	})
+	p.editBtn = widget.NewButton("Edit...", func() {
+		if p.selected < 0 {
+			return
+		}
+		i := p.selected
+		p.showEditor("Edit Bookmark", p.project.Bookmarks[i], func(b bookmarks.Bookmark) {
+			p.project.Replace(i, b)
+		})
+	})
+	p.deleteBtn = widget.NewButton("Delete", func() {
+		if p.selected < 0 {
+			return
+		}
+		i := p.selected
+		b := p.project.Bookmarks[i]
+		dialog.ShowConfirm("Delete Bookmark", fmt.Sprintf("Delete %q?", b.Name), func(ok bool) {
+			if ok {
+				p.project.Remove(i)
+				p.changed()
+			}
+		}, ctx.Window)
+	})
+	p.editBtn.Disable()
+	p.deleteBtn.Disable()
+
+	width := canvas.NewRectangle(color.Transparent)
+	width.SetMinSize(fyne.NewSize(260, 0))
+	heading := widget.NewLabel("Bookmarks")
+	heading.TextStyle = fyne.TextStyle{Bold: true}
+	p.content = container.NewBorder(
+		container.NewVBox(heading, p.title),
+		container.NewGridWithColumns(3, addBtn, p.editBtn, p.deleteBtn),
+		nil, nil,
+		container.NewStack(width, p.list),
+	)
+	p.content.Hide()
+	p.updateTitle()
+	return p
+}
+
+// Content returns the sidebar, hidden until toggled
+func (p *BookmarkPanel) Content() fyne.CanvasObject {
+	return p.content
+}
+
+// Toggle shows or hides the sidebar
+func (p *BookmarkPanel) Toggle() {
+	p.SetVisible(!p.Visible())
+}
+
+// Visible reports whether the sidebar is shown
+func (p *BookmarkPanel) Visible() bool {
+	return p.content.Visible()
+}
+
+// SetVisible shows or hides the sidebar
+func (p *BookmarkPanel) SetVisible(visible bool) {
+	if visible {
+		p.content.Show()
+	} else {
+		p.content.Hide()
+	}
+}
+
+// SetProject switches to the bookmarks saved for a firmware image, unless it
+// is already open
+func (p *BookmarkPanel) SetProject(key, name string) {
+	if key == p.project.Key {
+		return
+	}
+	project, err := bookmarks.Load(key, name)
+	if err != nil {
+		p.ctx.Output.SetText(fmt.Sprintf("Error: %v", err))
+	}
+	p.project = project
+	p.list.UnselectAll()
+	p.list.Refresh()
+	p.updateTitle()
+	p.ctx.HexView.Refresh()
+}
+
+// Bookmarks returns the bookmarks of the current project
+func (p *BookmarkPanel) Bookmarks() []bookmarks.Bookmark {
+	return slices.Clone(p.project.Bookmarks)
+}
+
+// Merge adds bookmarks, e.g. from a workspace, to the current project and saves it
+func (p *BookmarkPanel) Merge(bs []bookmarks.Bookmark) int {
+	added := p.project.Merge(bs)
+	if added > 0 {
+		p.changed()
+	}
+	return added
+}
+
+// At returns the bookmark covering addr
+func (p *BookmarkPanel) At(addr uint32) (bookmarks.Bookmark, bool) {
+	return p.project.At(addr)
+}
+
+// Find returns the bookmark with the given name
+func (p *BookmarkPanel) Find(name string) (bookmarks.Bookmark, bool) {
+	return p.project.Find(name)
+}
+
+// StartingIn returns the bookmarks that start in [start, end)
+func (p *BookmarkPanel) StartingIn(start, end uint32) []bookmarks.Bookmark {
+	return p.project.StartingIn(start, end)
+}
+
+func (p *BookmarkPanel) updateTitle() {
+	p.title.SetText(fmt.Sprintf("%s (%d)", p.project.Name, len(p.project.Bookmarks)))
+}
+
+// changed saves the project and redraws everything showing bookmarks
+func (p *BookmarkPanel) changed() {
+	p.list.UnselectAll()
+	p.list.Refresh()
+	p.updateTitle()
+	p.ctx.HexView.Refresh()
+	if err := p.project.Save(); err != nil {
+		dialog.ShowError(err, p.ctx.Window)
+	}
+}
+
+// showEditor asks for a bookmark's details and passes them to apply
+func (p *BookmarkPanel) showEditor(title string, b bookmarks.Bookmark, apply func(bookmarks.Bookmark)) {
+	nameEntry := widget.NewEntry()
+	nameEntry.SetText(b.Name)
+	addressEntry := widget.NewEntry()
+	addressEntry.SetText(fmt.Sprintf("0x%08x", b.Address))
+	lengthEntry := widget.NewEntry()
+	lengthEntry.SetText(strconv.Itoa(int(b.Length)))
+	typeEntry := widget.NewSelectEntry(bookmarks.Types)
+	typeEntry.SetText(b.Type)
+	commentEntry := widget.NewMultiLineEntry()
+	commentEntry.SetText(b.Comment)
+	commentEntry.SetMinRowsVisible(3)
+
+	items := []*widget.FormItem{
+		widget.NewFormItem("Name", nameEntry),
+		widget.NewFormItem("Address", addressEntry),
+		widget.NewFormItem("Length", lengthEntry),
+		widget.NewFormItem("Type", typeEntry),
+		widget.NewFormItem("Comment", commentEntry),
+	}
+	var form dialog.Dialog
+	form = dialog.NewForm(title, "Save", "Cancel", items, func(save bool) {
+		if !save {
+			return
+		}
+		p.readEditor(nameEntry.Text, addressEntry.Text, lengthEntry.Text, func(edited bookmarks.Bookmark, err error) {
+			if err != nil {
+				// Keep the form so the mistake can be fixed
+				form.Show()
+				dialog.ShowError(err, p.ctx.Window)
+				return
+			}
+			edited.Type = strings.TrimSpace(typeEntry.Text)
+			edited.Comment = strings.TrimSpace(commentEntry.Text)
+			apply(edited)
+			p.changed()
+		})
+	}, p.ctx.Window)
+	form.Resize(fyne.NewSize(460, 0))
+	form.Show()
+}
+
+// readEditor validates the name, address and length entered in the editor
+// and passes the bookmark to done. The address may be an expression.
+func (p *BookmarkPanel) readEditor(name, address, length string, done func(bookmarks.Bookmark, error)) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		done(bookmarks.Bookmark{}, fmt.Errorf("Give the bookmark a name"))
+		return
+	}
+	n, err := strconv.ParseUint(strings.TrimSpace(length), 0, 32)
+	if err != nil || n == 0 {
+		done(bookmarks.Bookmark{}, fmt.Errorf("Length must be a positive number"))
+		return
+	}
+	resolveAddress(p.ctx, address, func(addr uint32, err error) {
+		if err != nil {
+			done(bookmarks.Bookmark{}, err)
+			return
+		}
+		done(bookmarks.Bookmark{Name: name, Address: addr, Length: uint32(n)}, nil)
+	})
+}
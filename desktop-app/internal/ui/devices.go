@@ -0,0 +1,175 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/serial"
+	"github.com/MironCo/picopeeker/internal/util"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// BuildDeviceManager creates the device list UI. isBound reports ports already held
+// by a window (which can't be probed), onOpen binds a probed device to its own window.
+// The returned function updates the list when ports are hot-plugged and must be
+// called on the UI thread.
+func BuildDeviceManager(isBound func(string) bool, onOpen func(serial.Device)) (fyne.CanvasObject, func([]util.PortInfo)) {
+	var devices []serial.Device
+	probed := map[string]bool{}
+	selected := -1
+
+	statusLabel := widget.NewLabel("Looking for devices...")
+
+	deviceList := widget.NewList(
+		func() int { return len(devices) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return label
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(FormatDevice(devices[id]))
+		},
+	)
+	deviceList.OnSelected = func(id widget.ListItemID) {
+		selected = id
+	}
+	deviceList.OnUnselected = func(id widget.ListItemID) {
+		selected = -1
+	}
+
+	updateStatus := func() {
+		ready := 0
+		for _, device := range devices {
+			if device.Landmarks != "" {
+				ready++
+			}
+		}
+		statusLabel.SetText(fmt.Sprintf("%d PicoPeeker device(s) on %d port(s)", ready, len(devices)))
+	}
+
+	// probe opens the given ports off the UI thread and merges the results
+	probe := func(ports []util.PortInfo) {
+		if len(ports) == 0 {
+			return
+		}
+		statusLabel.SetText(fmt.Sprintf("Probing %d port(s)...", len(ports)))
+
+		go func() {
+			found := serial.ProbeDevices(ports)
+			fyne.Do(func() {
+				for _, device := range found {
+					for i := range devices {
+						if devices[i].Port == device.Port {
+							devices[i] = device
+						}
+					}
+				}
+				deviceList.Refresh()
+				updateStatus()
+			})
+		}()
+	}
+
+	// refresh rebuilds the list from the enumerated ports, probing only new ones
+	refresh := func(ports []util.PortInfo) {
+		previous := map[string]serial.Device{}
+		for _, device := range devices {
+			previous[device.Port] = device
+		}
+
+		devices = devices[:0]
+		newPorts := []util.PortInfo{}
+		present := map[string]bool{}
+		for _, port := range ports {
+			present[port.Name] = true
+			if device, ok := previous[port.Name]; ok && probed[port.Name] {
+				devices = append(devices, device)
+				continue
+			}
+			device := serial.Device{Port: port.Name, USB: port}
+			device.Info.Chip = port.Chip
+			device.Info.BoardID = port.SerialNumber
+			if isBound(port.Name) {
+				// The window holds the port open, so there's nothing to probe
+				device.Landmarks = "(open in a window)"
+			} else {
+				newPorts = append(newPorts, port)
+				probed[port.Name] = true
+			}
+			devices = append(devices, device)
+		}
+		for port := range probed {
+			if !present[port] {
+				delete(probed, port)
+			}
+		}
+
+		selected = -1
+		deviceList.UnselectAll()
+		deviceList.Refresh()
+		updateStatus()
+		probe(newPorts)
+	}
+
+	scanBtn := widget.NewButton("Rescan", func() {
+		ports, err := util.FindPicoPorts()
+		if err != nil {
+			statusLabel.SetText(fmt.Sprintf("Could not enumerate ports: %v", err))
+			return
+		}
+		if len(ports) == 0 {
+			devices = nil
+			deviceList.Refresh()
+			statusLabel.SetText("No Raspberry Pi serial ports found")
+			return
+		}
+		probed = map[string]bool{}
+		refresh(ports)
+	})
+
+	openBtn := widget.NewButton("Open in New Window", func() {
+		if selected < 0 || selected >= len(devices) {
+			statusLabel.SetText("Select a device first")
+			return
+		}
+		device := devices[selected]
+		if device.Landmarks == "" {
+			statusLabel.SetText(fmt.Sprintf("%s is not a PicoPeeker device", device.Port))
+			return
+		}
+		onOpen(device)
+	})
+	openBtn.Importance = widget.HighImportance
+
+	content := container.NewBorder(
+		statusLabel,
+		container.NewHBox(scanBtn, openBtn),
+		nil,
+		nil,
+		deviceList,
+	)
+	return content, refresh
+}
+
+// FormatDevice returns a one-line summary of a probed device
+func FormatDevice(device serial.Device) string {
+	chip := device.Info.Chip
+	if chip == "" {
+		chip = "unknown chip"
+	}
+	boardID := device.Info.BoardID
+	if boardID == "" {
+		boardID = "no serial"
+	}
+
+	switch {
+	case device.Err != nil:
+		return fmt.Sprintf("%s  %s  %s  (%v)", device.Port, chip, boardID, device.Err)
+	case device.Landmarks == "":
+		return fmt.Sprintf("%s  %s  %s  (probing...)", device.Port, chip, boardID)
+	default:
+		return fmt.Sprintf("%s  %s  %s  %s", device.Port, chip, boardID, device.Landmarks)
+	}
+}
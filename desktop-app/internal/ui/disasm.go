@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/disasm"
+	"fmt"
+	"strings"
+
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// DisassemblyView shows a listing in the output area, with branch targets as links
+type DisassemblyView struct {
+	text   *widget.RichText
+	scroll *container.Scroll
+	area   *OutputArea
+}
+
+// NewDisassemblyView adds a listing view to the output area
+func NewDisassemblyView(area *OutputArea) *DisassemblyView {
+	view := &DisassemblyView{
+		text: widget.NewRichText(),
+		area: area,
+	}
+	view.scroll = container.NewScroll(view.text)
+	area.Add(view.scroll)
+	return view
+}
+
+// Show displays the listing. Tapping a branch or literal target calls navigate with its address.
+func (v *DisassemblyView) Show(title string, insts []disasm.Instruction, symbolize disasm.Symbolizer, navigate func(uint32)) {
+	code := func(text string, inline bool) *widget.TextSegment {
+		style := widget.RichTextStyleCodeInline
+		if !inline {
+			style = widget.RichTextStyleCodeBlock
+		}
+		return &widget.TextSegment{Text: text, Style: style}
+	}
+
+	segments := []widget.RichTextSegment{code(fmt.Sprintf("=== %s ===", title), false)}
+	for _, inst := range insts {
+		// Label where a symbol starts
+		if symbolize != nil {
+			if name := symbolize(inst.Address); name != "" && !strings.Contains(name, "+") {
+				segments = append(segments, code(fmt.Sprintf("%08x <%s>:", inst.Address, name), false))
+			}
+		}
+
+		if !inst.HasTarget {
+			segments = append(segments, code(disasm.FormatLine(inst), false))
+			continue
+		}
+
+		target := inst.Target
+		link := &widget.HyperlinkSegment{Text: fmt.Sprintf("0x%08x", target)}
+		link.OnTapped = func() {
+			navigate(target)
+		}
+
+		annotation := " "
+		if symbolize != nil {
+			if name := symbolize(target); name != "" {
+				annotation = fmt.Sprintf(" <%s>", name)
+			}
+		}
+		segments = append(segments, code(disasm.FormatLine(inst)+"  ; ", true), link, code(annotation, false))
+	}
+
+	v.text.Segments = segments
+	v.text.Refresh()
+	v.scroll.ScrollToTop()
+	v.area.Show(v.scroll)
+}
@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/format"
+	"fmt"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// ShowExportDialog asks for an export format and file, then writes the block to it
+func ShowExportDialog(window fyne.Window, block format.MemoryBlock, onDone func(message string)) {
+	if len(block.Data) == 0 {
+		dialog.ShowInformation("Export", "Nothing to export - read some memory first", window)
+		return
+	}
+
+	formatSelect := widget.NewSelect(format.ExportFormats, nil)
+	formatSelect.SetSelected(format.ExportIntelHex)
+
+	form := container.NewVBox(
+		widget.NewLabel(fmt.Sprintf("%d bytes from 0x%08x", len(block.Data), block.Address)),
+		container.NewBorder(nil, nil, widget.NewLabel("Format:"), nil, formatSelect),
+	)
+
+	dialog.ShowCustomConfirm("Export Memory", "Save...", "Cancel", form, func(confirmed bool) {
+		if !confirmed {
+			return
+		}
+		exportFormat := formatSelect.Selected
+		extension := format.ExportExtension(exportFormat)
+
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil || writer == nil {
+				return
+			}
+			defer writer.Close()
+
+			// The file name wins if the user typed a known extension
+			chosen := exportFormat
+			if writer.URI().Extension() != extension {
+				chosen = format.ExportFormatForFile(writer.URI().Name())
+			}
+			if err := format.ExportBlock(writer, block, chosen); err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			onDone(fmt.Sprintf("Exported %d bytes from 0x%08x as %s to %s", len(block.Data), block.Address, chosen, writer.URI().Path()))
+		}, window)
+		saveDialog.SetFileName(fmt.Sprintf("mem_%08x%s", block.Address, extension))
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{extension}))
+		saveDialog.Show()
+	}, window)
+}
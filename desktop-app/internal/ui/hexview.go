@@ -0,0 +1,724 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/bookmarks"
+	"github.com/MironCo/picopeeker/internal/format"
+	"encoding/binary"
+	"fmt"
+	"image/color"
+	"math"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/driver/desktop"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+const (
+	hexRowBytes  = 16
+	hexPageSize  = 1024 // Bytes fetched per READ
+	hexMaxPages  = 512  // The cache is dropped when it grows past this
+	hexAddrChars = 8
+	hexStartCol  = hexAddrChars + 2
+	asciiCol     = hexStartCol + hexRowBytes*3 + 2 // "xx " per byte, an extra space in the middle, then a gap
+)
+
+// HexView is a hex viewer over a whole memory region. Rows are only built for
+// what is on screen and pages are read from the device as they scroll into
+// view. Outside the searchable regions it shows only the bytes asked for. Bytes can be selected by dragging or shift-clicking, and hovering a
+// byte shows it decoded in several formats.
+type HexView struct {
+	ctx  *DeviceContext
+	area *OutputArea
+
+	start, end uint32 // Range being viewed
+	regionName string
+	exact      bool // Only the range asked for is shown, see viewRange
+
+	pages      map[uint32][]byte // Finished reads by page address, nil if the read failed
+	requested  map[uint32]bool   // Reads started
+	generation int               // Bumped when the cache is dropped, so late reads are ignored
+
+	selStart, selEnd uint32 // Inclusive
+	hasSelection     bool
+	anchor           uint32
+
+	hovered        uint32
+	hoverRow       *hexRow
+	hasHover       bool
+	charWidth      float32
+	list           *widget.List
+	header         *widget.Label
+	status         *widget.Label
+	gotoEntry      *widget.Entry
+	tooltip        *fyne.Container
+	tipText        *widget.Label
+	tipLayer       *fyne.Container
+	fields         []format.InspectorField
+	inspector      *widget.List
+	inspectorPanel fyne.CanvasObject
+	content        fyne.CanvasObject
+}
+
+// NewHexView adds a hex viewer to the output area
+func NewHexView(ctx *DeviceContext, area *OutputArea) *HexView {
+	v := &HexView{
+		ctx:       ctx,
+		area:      area,
+		pages:     make(map[uint32][]byte),
+		requested: make(map[uint32]bool),
+		charWidth: fyne.MeasureText("0", theme.TextSize(), fyne.TextStyle{Monospace: true}).Width,
+	}
+
+	v.list = widget.NewList(
+		func() int { return int((v.end - v.start + hexRowBytes - 1) / hexRowBytes) },
+		func() fyne.CanvasObject { return newHexRow(v) },
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := item.(*hexRow)
+			row.addr = v.start + uint32(id)*hexRowBytes
+			row.Refresh()
+		},
+	)
+
+	v.header = widget.NewLabel("")
+	v.header.TextStyle = fyne.TextStyle{Monospace: true}
+	v.status = widget.NewLabel("")
+
+	v.gotoEntry = widget.NewEntry()
+	v.gotoEntry.SetPlaceHolder("Go to address, symbol or expression")
+	v.gotoEntry.OnSubmitted = func(string) {
+		v.gotoAddress()
+	}
+	gotoBtn := widget.NewButton("Go", v.gotoAddress)
+	refreshBtn := widget.NewButton("Refresh", func() {
+		v.dropCache()
+		v.list.Refresh()
+	})
+
+	// The tooltip floats over the list. Labels don't take mouse events, so
+	// it doesn't get in the way of hovering.
+	v.tipText = widget.NewLabel("")
+	v.tipText.TextStyle = fyne.TextStyle{Monospace: true}
+	background := canvas.NewRectangle(theme.Color(theme.ColorNameOverlayBackground))
+	background.StrokeColor = theme.Color(theme.ColorNameSeparator)
+	background.StrokeWidth = 1
+	v.tooltip = container.NewStack(background, v.tipText)
+	v.tooltip.Hide()
+	v.tipLayer = container.NewWithoutLayout(v.tooltip)
+
+	// The data inspector decodes the selection as every type at once
+	v.inspector = widget.NewList(
+		func() int { return len(v.fields) },
+		func() fyne.CanvasObject {
+			name := widget.NewLabel("time_t (64-bit)")
+			name.TextStyle = fyne.TextStyle{Bold: true}
+			value := widget.NewLabel("")
+			value.TextStyle = fyne.TextStyle{Monospace: true}
+			value.Truncation = fyne.TextTruncateEllipsis
+			return container.NewBorder(nil, nil, name, nil, value)
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			row := item.(*fyne.Container)
+			row.Objects[0].(*widget.Label).SetText(v.fields[id].Value)
+			row.Objects[1].(*widget.Label).SetText(v.fields[id].Name)
+		},
+	)
+	width := canvas.NewRectangle(color.Transparent)
+	width.SetMinSize(fyne.NewSize(340, 0))
+	inspectorTitle := widget.NewLabel("Data Inspector")
+	inspectorTitle.TextStyle = fyne.TextStyle{Bold: true}
+	v.inspectorPanel = container.NewBorder(inspectorTitle, nil, nil, nil, container.NewStack(width, v.inspector))
+
+	inspectorBtn := widget.NewButton("Inspector", func() {
+		v.SetInspectorVisible(!v.InspectorVisible())
+	})
+
+	toolbar := container.NewBorder(nil, nil, v.header, container.NewHBox(gotoBtn, refreshBtn, inspectorBtn), v.gotoEntry)
+	v.content = container.NewBorder(toolbar, v.status, nil, v.inspectorPanel, container.NewStack(v.list, v.tipLayer))
+	area.Add(v.content)
+	return v
+}
+
+// ShowBlock shows the viewer with a block that was just read selected. The
+// cache is dropped first, so the rest of the region is read fresh too.
+func (v *HexView) ShowBlock(block format.MemoryBlock) {
+	v.setRange(block.Address, uint32(len(block.Data)))
+	v.dropCache()
+
+	if v.exact {
+		// The view is the block, so nothing needs to be read again
+		for page := uint64(alignDown(v.start, hexPageSize)); page < uint64(v.end); page += hexPageSize {
+			from := max(uint32(page), v.start)
+			to := uint32(min(page+hexPageSize, uint64(v.end)))
+			v.pages[uint32(page)] = pageData(uint32(page), from, block.Data[from-v.start:to-v.start])
+		}
+	} else {
+		// Whole pages in the block don't need to be read again
+		for page := alignDown(block.Address+hexPageSize-1, hexPageSize); page+hexPageSize <= block.Address+uint32(len(block.Data)); page += hexPageSize {
+			offset := page - block.Address
+			v.pages[page] = block.Data[offset : offset+hexPageSize]
+		}
+	}
+
+	if len(block.Data) > 0 {
+		v.selStart, v.selEnd, v.hasSelection = block.Address, block.Address+uint32(len(block.Data))-1, true
+	}
+	v.scrollTo(block.Address)
+	v.updateStatus()
+	v.area.Show(v.content)
+}
+
+// Goto shows the viewer at an address without changing the device state
+func (v *HexView) Goto(addr uint32) {
+	v.Select(addr, 1)
+}
+
+// Select shows the viewer with length bytes at addr selected
+func (v *HexView) Select(addr, length uint32) {
+	v.setRange(addr, length)
+	last := addr + max(length, 1) - 1
+	if last < addr || last >= v.end {
+		last = v.end - 1 // Stay inside the region
+	}
+	v.selStart, v.selEnd, v.hasSelection = addr, last, true
+	v.anchor = addr
+	v.scrollTo(addr)
+	v.updateStatus()
+	v.area.Show(v.content)
+}
+
+// Selection returns the selected range
+func (v *HexView) Selection() (start, length uint32, ok bool) {
+	if !v.hasSelection {
+		return 0, 0, false
+	}
+	return v.selStart, v.selEnd - v.selStart + 1, true
+}
+
+// InspectorVisible reports whether the data inspector is shown
+func (v *HexView) InspectorVisible() bool {
+	return v.inspectorPanel.Visible()
+}
+
+// SetInspectorVisible shows or hides the data inspector
+func (v *HexView) SetInspectorVisible(visible bool) {
+	if visible {
+		v.inspectorPanel.Show()
+	} else {
+		v.inspectorPanel.Hide()
+	}
+}
+
+// Refresh redraws the rows, e.g. after the bookmarks change
+func (v *HexView) Refresh() {
+	v.list.Refresh()
+	v.updateStatus()
+}
+
+func (v *HexView) gotoAddress() {
+	resolveAddress(v.ctx, v.gotoEntry.Text, func(addr uint32, err error) {
+		if err == nil {
+			err = checkReadable(v.ctx.Regions(), addr)
+		}
+		if err != nil {
+			v.status.SetText(fmt.Sprintf("Error: %v", err))
+			return
+		}
+		v.Goto(addr)
+	})
+}
+
+// viewRange returns what to show for length bytes at addr. A searchable region
+// is shown whole, read a page at a time as it scrolls into view. Anywhere else,
+// like the peripherals where reads have side effects (FIFO pops, clear-on-read
+// status), only the bytes asked for are read.
+func (v *HexView) viewRange(addr, length uint32) (start, end uint32, name string, exact bool) {
+	region, ok := v.ctx.Regions().RegionAt(addr)
+	if ok && !region.NoSearch {
+		return region.Start, region.End, region.Name, false
+	}
+
+	end = addr + max(length, 1)
+	if end < addr {
+		end = math.MaxUint32 // Wrapped past the top of memory
+	}
+	name = "Unmapped"
+	if ok {
+		end = min(end, region.End)
+		name = region.Name
+	}
+	return addr, end, name, true
+}
+
+// setRange switches to the view for length bytes at addr
+func (v *HexView) setRange(addr, length uint32) {
+	start, end, name, exact := v.viewRange(addr, length)
+
+	if start != v.start || end != v.end {
+		v.start, v.end, v.regionName, v.exact = start, end, name, exact
+		v.hasSelection = false
+		v.dropCache()
+		v.list.Refresh()
+	}
+	v.header.SetText(fmt.Sprintf("%s 0x%08x-0x%08x", v.regionName, v.start, v.end))
+}
+
+func (v *HexView) scrollTo(addr uint32) {
+	row := int((addr - v.start) / hexRowBytes)
+	v.list.ScrollTo(row)
+	v.list.Refresh()
+}
+
+func (v *HexView) dropCache() {
+	v.pages = make(map[uint32][]byte)
+	v.requested = make(map[uint32]bool)
+	v.generation++
+}
+
+// byteAt returns a byte, fetching its page if needed. ok is false while the
+// page is loading; failed is set if the page could not be read.
+func (v *HexView) byteAt(addr uint32) (b byte, ok, failed bool) {
+	if addr < v.start || addr >= v.end {
+		return 0, false, true
+	}
+	page := alignDown(addr, hexPageSize)
+	data, done := v.pages[page]
+	if !done {
+		if !v.requested[page] {
+			v.fetch(page)
+		}
+		return 0, false, false
+	}
+	offset := int(addr - page)
+	if offset >= len(data) {
+		return 0, false, true
+	}
+	return data[offset], true, false
+}
+
+func (v *HexView) fetch(page uint32) {
+	if len(v.pages) >= hexMaxPages {
+		v.dropCache()
+	}
+	v.requested[page] = true
+	gen := v.generation
+	// Never read outside the view: an exact view may start and end mid-page
+	start := max(page, v.start)
+	length := min(hexPageSize, v.end-page) - (start - page)
+
+	go func() {
+		block, err := v.ctx.Session.ReadBlock(start, int(length))
+		fyne.Do(func() {
+			if gen != v.generation {
+				return
+			}
+			if err != nil || len(block.Data) == 0 {
+				v.pages[page] = nil // Shown as ??
+			} else {
+				v.pages[page] = pageData(page, start, block.Data)
+			}
+			v.list.Refresh()
+			v.updateInspector()
+		})
+	}()
+}
+
+// pageData returns data read from start as the cached page at page. Bytes
+// before start are outside the view, so they are never shown.
+func pageData(page, start uint32, data []byte) []byte {
+	if start == page {
+		return data
+	}
+	return append(make([]byte, start-page), data...)
+}
+
+// bytesFrom returns up to n loaded bytes starting at addr
+func (v *HexView) bytesFrom(addr uint32, n int) []byte {
+	var data []byte
+	for i := 0; i < n; i++ {
+		b, ok, _ := v.byteAt(addr + uint32(i))
+		if !ok {
+			break
+		}
+		data = append(data, b)
+	}
+	return data
+}
+
+func (v *HexView) selected(addr uint32) bool {
+	return v.hasSelection && addr >= v.selStart && addr <= v.selEnd
+}
+
+// selectTo selects from the anchor to addr
+func (v *HexView) selectTo(addr uint32) {
+	v.selStart, v.selEnd = min(v.anchor, addr), max(v.anchor, addr)
+	v.hasSelection = true
+	v.list.Refresh()
+	v.updateStatus()
+}
+
+// updateStatus describes the selection in the status bar and the inspector
+func (v *HexView) updateStatus() {
+	v.updateInspector()
+	if !v.hasSelection {
+		v.status.SetText("Hover a byte to decode it, drag or shift-click to select")
+		return
+	}
+	length := v.selEnd - v.selStart + 1
+	text := fmt.Sprintf("Selected 0x%08x-0x%08x (%d bytes)", v.selStart, v.selEnd, length)
+	if name := v.ctx.Symbols().Name(v.selStart); name != "" {
+		text += "  " + name
+	}
+	if b, ok := v.bookmarkAt(v.selStart); ok {
+		text += "  [" + b.Name + "]"
+	}
+	v.status.SetText(text)
+}
+
+func (v *HexView) updateInspector() {
+	if !v.hasSelection {
+		v.fields = nil
+		v.inspector.Refresh()
+		return
+	}
+	length := int(v.selEnd - v.selStart + 1)
+	data := v.bytesFrom(v.selStart, max(8, min(length, format.MaxInspectText)))
+	v.fields = format.Inspect(data, length, v.describeAddress)
+	v.inspector.Refresh()
+}
+
+// describeAddress names what an address points at: a symbol, or else its region
+func (v *HexView) describeAddress(addr uint32) string {
+	if name := v.ctx.Symbols().Name(addr); name != "" {
+		return name
+	}
+	if region, ok := v.ctx.Regions().RegionAt(addr); ok {
+		return "(" + region.Name + ")"
+	}
+	return ""
+}
+
+func (v *HexView) bookmarkAt(addr uint32) (bookmarks.Bookmark, bool) {
+	if v.ctx.Bookmarks == nil {
+		return bookmarks.Bookmark{}, false
+	}
+	return v.ctx.Bookmarks.At(addr)
+}
+
+// hover shows the tooltip for addr next to the pointer, at pos in the row's coordinates
+func (v *HexView) hover(row *hexRow, addr uint32, pos fyne.Position) {
+	if v.hasHover && v.hovered == addr && v.hoverRow == row {
+		v.placeTooltip(row, pos)
+		return
+	}
+	previous := v.hoverRow
+	v.hovered, v.hoverRow, v.hasHover = addr, row, true
+	if previous != nil && previous != row {
+		previous.Refresh()
+	}
+	row.Refresh()
+
+	data := v.bytesFrom(addr, 8)
+	if len(data) == 0 {
+		v.tooltip.Hide()
+		return
+	}
+	text := describeBytes(addr, data, v.ctx.Symbols().Name(addr))
+	if b, ok := v.bookmarkAt(addr); ok {
+		text += fmt.Sprintf("\n\n%s  0x%08x+%d", b.Name, b.Address, b.Length)
+		if b.Type != "" {
+			text += "  " + b.Type
+		}
+		if b.Comment != "" {
+			text += "\n" + b.Comment
+		}
+	}
+	v.tipText.SetText(text)
+	v.placeTooltip(row, pos)
+	v.tooltip.Show()
+}
+
+func (v *HexView) placeTooltip(row *hexRow, pos fyne.Position) {
+	driver := fyne.CurrentApp().Driver()
+	rel := driver.AbsolutePositionForObject(row).Add(pos).Subtract(driver.AbsolutePositionForObject(v.tipLayer))
+
+	size := v.tooltip.MinSize()
+	x, y := rel.X+16, rel.Y+16
+	if bounds := v.tipLayer.Size(); x+size.Width > bounds.Width {
+		x = max(0, rel.X-size.Width-8)
+		if y+size.Height > bounds.Height {
+			y = max(0, rel.Y-size.Height-8)
+		}
+	} else if y+size.Height > bounds.Height {
+		y = max(0, rel.Y-size.Height-8)
+	}
+	v.tooltip.Resize(size)
+	v.tooltip.Move(fyne.NewPos(x, y))
+}
+
+func (v *HexView) unhover(row *hexRow) {
+	if v.hoverRow != row {
+		return
+	}
+	v.hasHover, v.hoverRow = false, nil
+	v.tooltip.Hide()
+	row.Refresh()
+}
+
+// describeBytes decodes the bytes at addr in the common formats
+func describeBytes(addr uint32, data []byte, symbol string) string {
+	var lines []string
+	title := fmt.Sprintf("0x%08x", addr)
+	if symbol != "" {
+		title += "  " + symbol
+	}
+	lines = append(lines, title)
+
+	b := data[0]
+	char := ""
+	if b >= 0x20 && b < 0x7F {
+		char = fmt.Sprintf("  '%c'", b)
+	}
+	lines = append(lines, fmt.Sprintf("u8   %d  i8 %d  0b%08b%s", b, int8(b), b, char))
+	if len(data) >= 2 {
+		u := binary.LittleEndian.Uint16(data)
+		lines = append(lines, fmt.Sprintf("u16  %d  i16 %d  BE %d", u, int16(u), binary.BigEndian.Uint16(data)))
+	}
+	if len(data) >= 4 {
+		u := binary.LittleEndian.Uint32(data)
+		lines = append(lines, fmt.Sprintf("u32  %d  i32 %d  0x%08x", u, int32(u), u))
+		lines = append(lines, fmt.Sprintf("BE   %d  f32 %g", binary.BigEndian.Uint32(data), math.Float32frombits(u)))
+	}
+	if len(data) >= 8 {
+		u := binary.LittleEndian.Uint64(data)
+		lines = append(lines, fmt.Sprintf("u64  %d  f64 %g", u, math.Float64frombits(u)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// withAlpha returns c made translucent
+func withAlpha(c color.Color, alpha uint8) color.Color {
+	nrgba := color.NRGBAModel.Convert(c).(color.NRGBA)
+	nrgba.A = alpha
+	return nrgba
+}
+
+func alignDown(addr, size uint32) uint32 {
+	return addr &^ (size - 1)
+}
+
+// hexRow draws one row of the viewer and turns mouse positions into addresses
+type hexRow struct {
+	widget.BaseWidget
+	view *HexView
+	addr uint32
+}
+
+func newHexRow(view *HexView) *hexRow {
+	row := &hexRow{view: view}
+	row.ExtendBaseWidget(row)
+	return row
+}
+
+// byteIndex returns which byte of the row is under x, in either column
+func (r *hexRow) byteIndex(x float32) (int, bool) {
+	col := int(x / r.view.charWidth)
+	switch {
+	case col >= asciiCol && col < asciiCol+hexRowBytes:
+		return col - asciiCol, true
+	case col >= hexStartCol && col < asciiCol-2:
+		rel := col - hexStartCol
+		if rel >= 8*3 {
+			rel-- // The extra space between the two halves
+		}
+		return min(rel/3, hexRowBytes-1), true
+	}
+	return 0, false
+}
+
+// addrAt returns the address under pos, following drags into other rows
+func (r *hexRow) addrAt(pos fyne.Position) (uint32, bool) {
+	i, ok := r.byteIndex(pos.X)
+	if !ok {
+		return 0, false
+	}
+	height := r.Size().Height
+	rows := int64(0)
+	if height > 0 {
+		rows = int64(math.Floor(float64(pos.Y / height)))
+	}
+	addr := int64(r.addr) + rows*hexRowBytes + int64(i)
+	addr = max(int64(r.view.start), min(addr, int64(r.view.end)-1))
+	return uint32(addr), true
+}
+
+// MouseDown starts a selection, or extends it with shift held
+func (r *hexRow) MouseDown(e *desktop.MouseEvent) {
+	addr, ok := r.addrAt(e.Position)
+	if !ok {
+		return
+	}
+	if e.Modifier&fyne.KeyModifierShift == 0 || !r.view.hasSelection {
+		r.view.anchor = addr
+	}
+	r.view.selectTo(addr)
+}
+
+// MouseUp is required by desktop.Mouseable
+func (r *hexRow) MouseUp(*desktop.MouseEvent) {}
+
+// Tapped keeps taps from selecting the list row; MouseDown does the work
+func (r *hexRow) Tapped(*fyne.PointEvent) {}
+
+// Dragged extends the selection, including into other rows
+func (r *hexRow) Dragged(e *fyne.DragEvent) {
+	if addr, ok := r.addrAt(e.Position); ok {
+		r.view.selectTo(addr)
+	}
+}
+
+// DragEnd is required by fyne.Draggable
+func (r *hexRow) DragEnd() {}
+
+// MouseIn shows the tooltip for the byte under the pointer
+func (r *hexRow) MouseIn(e *desktop.MouseEvent) {
+	r.MouseMoved(e)
+}
+
+// MouseMoved follows the pointer across bytes
+func (r *hexRow) MouseMoved(e *desktop.MouseEvent) {
+	i, ok := r.byteIndex(e.Position.X)
+	if !ok {
+		r.view.unhover(r)
+		return
+	}
+	r.view.hover(r, r.addr+uint32(i), e.Position)
+}
+
+// MouseOut hides the tooltip
+func (r *hexRow) MouseOut() {
+	r.view.unhover(r)
+}
+
+func (r *hexRow) CreateRenderer() fyne.WidgetRenderer {
+	style := fyne.TextStyle{Monospace: true}
+	rend := &hexRowRenderer{
+		row:     r,
+		address: canvas.NewText("", theme.Color(theme.ColorNameForeground)),
+		hex:     canvas.NewText("", theme.Color(theme.ColorNameForeground)),
+		ascii:   canvas.NewText("", theme.Color(theme.ColorNameForeground)),
+		labels:  canvas.NewText("", theme.Color(theme.ColorNamePrimary)),
+	}
+	for _, text := range []*canvas.Text{rend.address, rend.hex, rend.ascii, rend.labels} {
+		text.TextStyle = style
+	}
+	rend.address.Color = theme.Color(theme.ColorNamePlaceHolder)
+	for i := range rend.marks {
+		rend.marks[i] = canvas.NewRectangle(color.Transparent)
+	}
+	return rend
+}
+
+type hexRowRenderer struct {
+	row                 *hexRow
+	address, hex, ascii *canvas.Text
+	labels              *canvas.Text                       // Names of the bookmarks starting in the row
+	marks               [hexRowBytes * 2]*canvas.Rectangle // Hex cells, then ASCII cells
+}
+
+func (r *hexRowRenderer) Layout(size fyne.Size) {
+	cw := r.row.view.charWidth
+	pad := theme.InnerPadding() / 2
+	textHeight := r.hex.MinSize().Height
+	y := (size.Height - textHeight) / 2
+
+	r.address.Move(fyne.NewPos(0, y))
+	r.hex.Move(fyne.NewPos(hexStartCol*cw, y))
+	r.ascii.Move(fyne.NewPos(asciiCol*cw, y))
+	r.labels.Move(fyne.NewPos((asciiCol+hexRowBytes+2)*cw, y))
+
+	for i := 0; i < hexRowBytes; i++ {
+		col := hexStartCol + i*3
+		if i >= 8 {
+			col++
+		}
+		r.marks[i].Move(fyne.NewPos(float32(col)*cw-pad/2, 0))
+		r.marks[i].Resize(fyne.NewSize(2*cw+pad, size.Height))
+		r.marks[hexRowBytes+i].Move(fyne.NewPos(float32(asciiCol+i)*cw, 0))
+		r.marks[hexRowBytes+i].Resize(fyne.NewSize(cw, size.Height))
+	}
+}
+
+func (r *hexRowRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(float32(asciiCol+hexRowBytes)*r.row.view.charWidth, r.hex.MinSize().Height+theme.InnerPadding()/2)
+}
+
+func (r *hexRowRenderer) Refresh() {
+	v, addr := r.row.view, r.row.addr
+	r.address.Text = fmt.Sprintf("%08x", addr)
+
+	var hex, ascii strings.Builder
+	selection := theme.Color(theme.ColorNameSelection)
+	hover := theme.Color(theme.ColorNameHover)
+	marked := withAlpha(theme.Color(theme.ColorNamePrimary), 0x30)
+	for i := 0; i < hexRowBytes; i++ {
+		if i == 8 {
+			hex.WriteByte(' ')
+		}
+		a := addr + uint32(i)
+		b, ok, failed := v.byteAt(a)
+		switch {
+		case ok:
+			fmt.Fprintf(&hex, "%02x ", b)
+			if b >= 0x20 && b < 0x7F {
+				ascii.WriteByte(b)
+			} else {
+				ascii.WriteByte('.')
+			}
+		case failed:
+			hex.WriteString("?? ")
+			ascii.WriteByte(' ')
+		default:
+			hex.WriteString(".. ")
+			ascii.WriteByte(' ')
+		}
+
+		fill := color.Color(color.Transparent)
+		if v.selected(a) {
+			fill = selection
+		} else if v.hasHover && v.hovered == a && v.hoverRow == r.row {
+			fill = hover
+		} else if _, ok := v.bookmarkAt(a); ok {
+			fill = marked
+		}
+		r.marks[i].FillColor = fill
+		r.marks[hexRowBytes+i].FillColor = fill
+	}
+	r.hex.Text = hex.String()
+	r.ascii.Text = ascii.String()
+
+	var names []string
+	if v.ctx.Bookmarks != nil {
+		for _, b := range v.ctx.Bookmarks.StartingIn(addr, addr+hexRowBytes) {
+			names = append(names, b.Name)
+		}
+	}
+	r.labels.Text = strings.Join(names, ", ")
+
+	for _, obj := range r.Objects() {
+		obj.Refresh()
+	}
+}
+
+func (r *hexRowRenderer) Objects() []fyne.CanvasObject {
+	objects := make([]fyne.CanvasObject, 0, len(r.marks)+4)
+	for _, mark := range r.marks {
+		objects = append(objects, mark)
+	}
+	return append(objects, r.address, r.hex, r.ascii, r.labels)
+}
+
+func (r *hexRowRenderer) Destroy() {}
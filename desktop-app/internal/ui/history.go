@@ -0,0 +1,72 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/workspace"
+	"slices"
+)
+
+// maxHistory is how many reads Back can step through
+const maxHistory = 100
+
+// maxRecentAddresses is how many addresses the address dropdown offers
+const maxRecentAddresses = 15
+
+// readHistory is the Read tab's back/forward list of read locations. Addresses
+// are stored resolved, so going back to a dereferenced pointer reads the same
+// place even if the pointer has changed since.
+type readHistory struct {
+	entries []workspace.Read
+	pos     int // Index of the current entry when there are any
+}
+
+// visit records a read, dropping anything ahead of the current entry
+func (h *readHistory) visit(loc workspace.Read) {
+	if len(h.entries) > 0 {
+		if h.entries[h.pos] == loc {
+			return
+		}
+		h.entries = h.entries[:h.pos+1]
+	}
+	h.entries = append(h.entries, loc)
+	if len(h.entries) > maxHistory {
+		h.entries = slices.Clone(h.entries[len(h.entries)-maxHistory:])
+	}
+	h.pos = len(h.entries) - 1
+}
+
+func (h *readHistory) canBack() bool {
+	return h.pos > 0
+}
+
+func (h *readHistory) canForward() bool {
+	return h.pos < len(h.entries)-1
+}
+
+// back moves to the previous read, if there is one
+func (h *readHistory) back() (workspace.Read, bool) {
+	if !h.canBack() {
+		return workspace.Read{}, false
+	}
+	h.pos--
+	return h.entries[h.pos], true
+}
+
+// forward moves to the next read after going back
+func (h *readHistory) forward() (workspace.Read, bool) {
+	if !h.canForward() {
+		return workspace.Read{}, false
+	}
+	h.pos++
+	return h.entries[h.pos], true
+}
+
+// recentAddresses lists the addresses read, most recent first, without repeats
+func (h *readHistory) recentAddresses() []string {
+	var recent []string
+	for i := len(h.entries) - 1; i >= 0 && len(recent) < maxRecentAddresses; i-- {
+		if !slices.Contains(recent, h.entries[i].Address) {
+			recent = append(recent, h.entries[i].Address)
+		}
+	}
+	return recent
+}
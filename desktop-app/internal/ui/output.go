@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// OutputArea is the space under the tabs. It shows the output entry, or one
+// of the richer views (disassembly, hex viewer) in its place, and switches
+// back to the output as soon as anything is written to it.
+type OutputArea struct {
+	outputScroll *container.Scroll
+	views        []fyne.CanvasObject
+	stack        *fyne.Container
+}
+
+// NewOutputArea wraps the output entry in an area that can also show other views
+func NewOutputArea(output *widget.Entry) *OutputArea {
+	area := &OutputArea{outputScroll: container.NewScroll(output)}
+	area.stack = container.NewStack(area.outputScroll)
+
+	output.OnChanged = func(string) {
+		area.ShowOutput()
+	}
+	return area
+}
+
+// Content returns the area, to be placed where the output entry would go
+func (a *OutputArea) Content() fyne.CanvasObject {
+	return a.stack
+}
+
+// Add registers a view that can be shown in place of the output. It starts hidden.
+func (a *OutputArea) Add(view fyne.CanvasObject) {
+	view.Hide()
+	a.views = append(a.views, view)
+	a.stack.Add(view)
+}
+
+// Show displays one of the added views
+func (a *OutputArea) Show(view fyne.CanvasObject) {
+	a.outputScroll.Hide()
+	for _, v := range a.views {
+		if v != view {
+			v.Hide()
+		}
+	}
+	view.Show()
+}
+
+// ShowOutput returns to the plain output entry
+func (a *OutputArea) ShowOutput() {
+	for _, v := range a.views {
+		v.Hide()
+	}
+	a.outputScroll.Show()
+}
@@ -0,0 +1,246 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/config"
+	"github.com/MironCo/picopeeker/internal/pointers"
+	"github.com/MironCo/picopeeker/internal/serial"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// maxPointerPaths caps a scan so deep, wide searches stay responsive
+const maxPointerPaths = 5000
+
+// BuildPointerScanTab creates the Pointer Scan tab UI, which finds pointer
+// chains that lead to an address and narrows them down across device resets
+func BuildPointerScanTab(ctx *DeviceContext) *container.TabItem {
+	session, output := ctx.Session, ctx.Output
+
+	var paths []pointers.Path
+	var scans int // Snapshots the current paths have survived
+	var stop chan struct{}
+
+	targetEntry := widget.NewEntry()
+	targetEntry.SetPlaceHolder("0x20001234, symbol or expression")
+
+	maxOffsetEntry := widget.NewEntry()
+	maxOffsetEntry.SetText("0x400")
+
+	depthSelect := widget.NewSelect([]string{"1", "2", "3", "4"}, nil)
+	depthSelect.SetSelected("2")
+
+	includeFlash := widget.NewCheck("Also scan Flash (slow)", nil)
+
+	progress := widget.NewProgressBar()
+	progress.Hide()
+
+	statusLabel := widget.NewLabel("No scan yet")
+
+	pathList := widget.NewList(
+		func() int { return len(paths) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(paths[id].Format(ctx.Symbols().Name))
+		},
+	)
+	pathList.OnSelected = func(id widget.ListItemID) {
+		ctx.Navigate(paths[id].Base)
+		pathList.UnselectAll()
+	}
+
+	var scanBtn, rescanBtn, stopBtn *widget.Button
+	setBusy := func(busy bool) {
+		if busy {
+			progress.SetValue(0)
+			progress.Show()
+			scanBtn.Disable()
+			rescanBtn.Disable()
+			stopBtn.Enable()
+			return
+		}
+		progress.Hide()
+		scanBtn.Enable()
+		if len(paths) > 0 {
+			rescanBtn.Enable()
+		} else {
+			rescanBtn.Disable()
+		}
+		stopBtn.Disable()
+	}
+
+	// snapshot reads SRAM (and flash if asked) into memory for scanning
+	snapshot := func(withFlash bool, stop chan struct{}) (pointers.Snapshot, error) {
+		regions := ctx.Regions()
+		type region struct {
+			name   string
+			start  uint32
+			length uint32
+		}
+		toRead := []region{{"SRAM", config.SRAMStart, regions.SRAMSizeHex}}
+		if withFlash {
+			toRead = append(toRead, region{"Flash", config.FlashStart, regions.FlashSizeHex})
+		}
+
+		var total, done uint32
+		for _, r := range toRead {
+			total += r.length
+		}
+
+		var snap pointers.Snapshot
+		for _, r := range toRead {
+			fyne.Do(func() {
+				output.SetText(fmt.Sprintf("Reading %s (%s)...", r.name, config.FormatSize(r.length)))
+			})
+			before := done
+			onProgress := func(n, _ int) {
+				fyne.Do(func() {
+					progress.SetValue(float64(before+uint32(n)) / float64(total))
+				})
+			}
+			block, err := session.ReadRegion(r.start, int(r.length), onProgress, stop)
+			if err != nil {
+				return nil, err
+			}
+			snap = append(snap, block)
+			done += r.length
+		}
+		return snap, nil
+	}
+
+	// readInputs validates the target and scan options and passes them to done
+	readInputs := func(done func(target uint32, opts pointers.Options, err error)) {
+		maxOffset, err := strconv.ParseUint(strings.TrimSpace(maxOffsetEntry.Text), 0, 32)
+		if err != nil {
+			done(0, pointers.Options{}, fmt.Errorf("Max offset must be a number (e.g., 0x400)"))
+			return
+		}
+		depth, _ := strconv.Atoi(depthSelect.Selected)
+		opts := pointers.Options{MaxOffset: uint32(maxOffset), MaxDepth: depth, MaxPaths: maxPointerPaths}
+		resolveAddress(ctx, targetEntry.Text, func(target uint32, err error) {
+			if err != nil {
+				done(0, opts, fmt.Errorf("Target: %v", err))
+				return
+			}
+			done(target, opts, nil)
+		})
+	}
+
+	// run takes a snapshot and processes it in the background. process
+	// returns the function that shows the result on the UI thread.
+	run := func(process func(snap pointers.Snapshot) func()) {
+		if session.Port() == "" {
+			output.SetText("Error: Please enter a port name")
+			return
+		}
+		stop = make(chan struct{})
+		setBusy(true)
+
+		go func(withFlash bool, stop chan struct{}) {
+			snap, err := snapshot(withFlash, stop)
+			var show func()
+			if err == nil {
+				show = process(snap)
+			}
+			fyne.Do(func() {
+				defer setBusy(false)
+				switch {
+				case errors.Is(err, serial.ErrStopped):
+					output.SetText("Stopped - the scan needs a complete snapshot, nothing was changed")
+				case err != nil:
+					output.SetText(fmt.Sprintf("Error: %v", err))
+				default:
+					show()
+				}
+			})
+		}(includeFlash.Checked, stop)
+	}
+
+	scanBtn = widget.NewButton("New Scan", func() {
+		readInputs(func(target uint32, opts pointers.Options, err error) {
+			if err != nil {
+				output.SetText(fmt.Sprintf("Error: %v", err))
+				return
+			}
+			output.SetText("Taking a snapshot...")
+			run(func(snap pointers.Snapshot) func() {
+				found, truncated := pointers.Scan(snap, target, opts)
+				return func() {
+					paths, scans = found, 1
+					pathList.ScrollToTop()
+					pathList.Refresh()
+
+					statusLabel.SetText(fmt.Sprintf("%d path(s) to 0x%08x", len(paths), target))
+					message := fmt.Sprintf("Found %d pointer path(s) to 0x%08x. Reset the device, enter the target's new address and use Rescan After Reset to keep the stable ones.", len(paths), target)
+					if truncated {
+						message += fmt.Sprintf("\nStopped at %d paths - lower the depth or max offset to see them all.", maxPointerPaths)
+					}
+					output.SetText(message)
+				}
+			})
+		})
+	})
+	scanBtn.Importance = widget.HighImportance
+
+	// The target may move after a reset, so it is read again from the entry
+	rescanBtn = widget.NewButton("Rescan After Reset", func() {
+		readInputs(func(target uint32, _ pointers.Options, err error) {
+			if err != nil {
+				output.SetText(fmt.Sprintf("Error: %v", err))
+				return
+			}
+			candidates := paths
+			output.SetText("Taking a snapshot...")
+			run(func(snap pointers.Snapshot) func() {
+				kept := pointers.Filter(candidates, snap, target)
+				return func() {
+					paths = kept
+					scans++
+					pathList.ScrollToTop()
+					pathList.Refresh()
+
+					statusLabel.SetText(fmt.Sprintf("%d path(s) to 0x%08x, stable across %d scans", len(paths), target, scans))
+					output.SetText(fmt.Sprintf("%d of %d path(s) still lead to 0x%08x", len(paths), len(candidates), target))
+				}
+			})
+		})
+	})
+
+	stopBtn = widget.NewButton("Stop", func() {
+		if stop != nil {
+			close(stop)
+			stop = nil
+		}
+		stopBtn.Disable()
+	})
+	setBusy(false)
+
+	settingsCard := widget.NewCard("", "", container.NewPadded(container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("Target:"), nil, targetEntry),
+		container.NewGridWithColumns(2,
+			container.NewBorder(nil, nil, widget.NewLabel("Max offset (±):"), nil, maxOffsetEntry),
+			container.NewBorder(nil, nil, widget.NewLabel("Max depth:"), nil, depthSelect),
+		),
+		includeFlash,
+	)))
+
+	pointerTab := container.NewVBox(
+		settingsCard,
+		container.NewBorder(nil, nil, nil, container.NewHBox(rescanBtn, stopBtn), scanBtn),
+		progress,
+		statusLabel,
+		withMinHeight(pathList, 200),
+	)
+
+	return container.NewTabItem("Pointer Scan", pointerTab)
+}
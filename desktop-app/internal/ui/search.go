@@ -0,0 +1,525 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/config"
+	"github.com/MironCo/picopeeker/internal/format"
+	"github.com/MironCo/picopeeker/internal/serial"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Each hit is previewed with contextSize bytes starting contextBefore bytes ahead of it
+const (
+	contextSize   = 32
+	contextBefore = 8
+)
+
+// BuildSearchMemoryTab creates the Search Memory tab UI. The returned function
+// refreshes the ELF section list after symbols are loaded.
+func BuildSearchMemoryTab(ctx *DeviceContext) (*container.TabItem, func()) {
+	session, output, getRegions := ctx.Session, ctx.Output, ctx.Regions
+
+	searchEntry := widget.NewEntry()
+	searchEntry.SetPlaceHolder("Enter pattern...")
+	searchEntry.SetText("")
+
+	searchTypeSelect := widget.NewSelect([]string{"Hex Bytes", "ASCII String", "32-bit Int (LE)"}, func(value string) {
+		switch value {
+		case "Hex Bytes":
+			searchEntry.SetPlaceHolder("DE AD ?? EF or 0x1F/0x1F")
+		case "ASCII String":
+			searchEntry.SetPlaceHolder("hello world")
+		case "32-bit Int (LE)":
+			searchEntry.SetPlaceHolder("42")
+		}
+	})
+	searchTypeSelect.SetSelected("Hex Bytes")
+
+	// Custom ranges take addresses or symbol names, e.g. __bss_start__ to __bss_end__
+	rangeStartEntry := widget.NewEntry()
+	rangeStartEntry.SetPlaceHolder("0x20000000, symbol or expression")
+	rangeEndEntry := widget.NewEntry()
+	rangeEndEntry.SetPlaceHolder("sram_end, buf+0x100... (exclusive)")
+	rangeRow := container.NewGridWithColumns(2,
+		container.NewBorder(nil, nil, widget.NewLabel("Start:"), nil, rangeStartEntry),
+		container.NewBorder(nil, nil, widget.NewLabel("End:"), nil, rangeEndEntry),
+	)
+	rangeRow.Hide()
+
+	sectionSelect := widget.NewSelect(nil, nil)
+	sectionSelect.PlaceHolder = "(load an ELF first)"
+	sectionRow := container.NewBorder(nil, nil, widget.NewLabel("Section:"), nil, sectionSelect)
+	sectionRow.Hide()
+
+	refreshSections := func() {
+		var names []string
+		for _, sec := range ctx.Symbols().Sections() {
+			names = append(names, fmt.Sprintf("%s (0x%08x, %s)", sec.Name, sec.Address, config.FormatSize(sec.Size)))
+		}
+		sectionSelect.SetOptions(names)
+		if len(names) == 0 {
+			sectionSelect.ClearSelected()
+		}
+	}
+
+	searchRegionSelect := widget.NewSelect(nil, func(value string) {
+		rangeRow.Hide()
+		sectionRow.Hide()
+		switch value {
+		case "Custom Range":
+			rangeRow.Show()
+		case "ELF Section":
+			refreshSections()
+			sectionRow.Show()
+		}
+	})
+	// The regions come from the memory map, which changes with the board
+	refreshRegions := func() {
+		selected := searchRegionSelect.Selected
+		options := append(regionNames(getRegions()), "Custom Range", "ELF Section")
+		searchRegionSelect.SetOptions(options)
+		if slices.Contains(options, selected) {
+			searchRegionSelect.SetSelected(selected)
+		} else {
+			searchRegionSelect.SetSelected(options[0])
+		}
+	}
+	refreshRegions()
+
+	// searchRange passes the [start, end) range to search for a region choice
+	// to done. Custom ranges are address expressions, which may read from the device.
+	searchRange := func(choice string, done func(start, end uint32, err error)) {
+		if region, ok := getRegions().Find(choice); ok {
+			done(region.Start, region.End, nil)
+			return
+		}
+		switch choice {
+		case "ELF Section":
+			if sectionSelect.SelectedIndex() < 0 {
+				done(0, 0, fmt.Errorf("Choose a section (load the firmware's ELF with Load ELF... first)"))
+				return
+			}
+			sec := ctx.Symbols().Sections()[sectionSelect.SelectedIndex()]
+			done(sec.Address, sec.Address+sec.Size, nil)
+			return
+		}
+		resolveAddress(ctx, rangeStartEntry.Text, func(start uint32, err error) {
+			if err != nil {
+				done(0, 0, fmt.Errorf("Start: %v", err))
+				return
+			}
+			resolveAddress(ctx, rangeEndEntry.Text, func(end uint32, err error) {
+				if err != nil {
+					done(0, 0, fmt.Errorf("End: %v", err))
+					return
+				}
+				if end <= start {
+					done(0, 0, fmt.Errorf("End must be after start"))
+					return
+				}
+				done(start, end, nil)
+			})
+		})
+	}
+
+	// Helper function to validate and convert search pattern
+	validateAndConvertPattern := func() (string, error) {
+		pattern := searchEntry.Text
+		searchType := searchTypeSelect.Selected
+
+		if pattern == "" {
+			return "", fmt.Errorf("Please enter a search pattern")
+		}
+
+		// Convert pattern to hex based on type
+		hexPattern := ""
+		switch searchType {
+		case "Hex Bytes":
+			// Wildcards (??) and masks (0x1F/0x1F) are sent as HEX/MASK
+			parsed, err := format.ParsePattern(pattern)
+			if err != nil {
+				return "", err
+			}
+			hexPattern = parsed.Wire()
+		case "ASCII String":
+			if pattern == "" {
+				return "", fmt.Errorf("ASCII string cannot be empty")
+			}
+			hexPattern = format.StringToHex(pattern)
+		case "32-bit Int (LE)":
+			val, err := strconv.ParseInt(pattern, 10, 32)
+			if err != nil {
+				return "", fmt.Errorf("Invalid integer: %v", err)
+			}
+			hexPattern = format.Int32ToHexLE(int32(val))
+		}
+		return hexPattern, nil
+	}
+
+	// Info label that updates based on the detected (or selected) model
+	infoLabel := widget.NewLabel("")
+	updateInfoLabel := func() {
+		regions := getRegions()
+		infoLabel.SetText(fmt.Sprintf("SRAM: Runtime data (%s) | Flash: String literals (%s)", regions.SRAMSize, regions.FlashSize))
+	}
+
+	// Hits are fetched a page at a time with SEARCHPAGE, so there is no limit
+	// on how many a search can return
+	var hits []uint32
+	var query struct {
+		start, end uint32
+		pattern    string
+		length     int // Pattern length in bytes, for highlighting
+		next       uint32
+		more       bool
+		total      int // Hits in the whole range, -1 until SEARCHCOUNT answers
+	}
+	var busy bool
+	var stop chan struct{}
+
+	// Context windows are read as rows scroll into view. generation changes
+	// with each search so late reads from the previous one are dropped.
+	contexts := make(map[uint32]*format.MemoryBlock)
+	generation := 0
+
+	var hitList *widget.List
+	fetchContext := func(hit uint32) {
+		contexts[hit] = nil // Pending
+		start := hit - min(hit-query.start, contextBefore)
+		gen := generation
+		go func() {
+			block, err := session.ReadBlock(start, contextSize)
+			fyne.Do(func() {
+				if gen != generation {
+					return
+				}
+				if err != nil {
+					block = format.MemoryBlock{Address: start} // Shown as unreadable
+				}
+				contexts[hit] = &block
+				hitList.Refresh()
+			})
+		}()
+	}
+
+	hitList = widget.NewList(
+		func() int { return len(hits) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			label.Truncation = fyne.TextTruncateEllipsis
+			preview := widget.NewRichText()
+			preview.Truncation = fyne.TextTruncateEllipsis
+			openBtn := widget.NewButton("Open in Read", nil)
+			return container.NewBorder(nil, nil, nil, openBtn, container.NewVBox(label, preview))
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			hit := hits[id]
+			row := item.(*fyne.Container)
+			rows := row.Objects[0].(*fyne.Container)
+			label, preview := rows.Objects[0].(*widget.Label), rows.Objects[1].(*widget.RichText)
+			openBtn := row.Objects[1].(*widget.Button)
+
+			text := fmt.Sprintf("0x%08x", hit)
+			if name := ctx.Symbols().Name(hit); name != "" {
+				text += "  " + name
+			}
+			label.SetText(text)
+			openBtn.OnTapped = func() {
+				ctx.Navigate(hit)
+			}
+
+			block, requested := contexts[hit]
+			if !requested {
+				fetchContext(hit)
+			}
+			preview.Segments = contextSegments(block, hit, query.length)
+			preview.Refresh()
+		},
+	)
+
+	countLabel := widget.NewLabel("No search yet")
+	var searchBtn, loadMoreBtn, loadAllBtn, stopBtn, exportBtn *widget.Button
+
+	updateControls := func() {
+		loaded := fmt.Sprintf("%d hit(s) so far", len(hits))
+		if query.total >= 0 {
+			loaded = fmt.Sprintf("%d of %d hit(s) loaded", len(hits), query.total)
+		}
+		switch {
+		case busy:
+			countLabel.SetText(loaded + ", searching...")
+		case query.more:
+			countLabel.SetText(fmt.Sprintf("%s, more after 0x%08x", loaded, query.next))
+		case query.pattern != "":
+			countLabel.SetText(fmt.Sprintf("%d hit(s) in total", len(hits)))
+		}
+		for _, btn := range []*widget.Button{searchBtn, exportBtn} {
+			if busy {
+				btn.Disable()
+			} else {
+				btn.Enable()
+			}
+		}
+		for _, btn := range []*widget.Button{loadMoreBtn, loadAllBtn} {
+			if busy || !query.more {
+				btn.Disable()
+			} else {
+				btn.Enable()
+			}
+		}
+		if busy {
+			stopBtn.Enable()
+		} else {
+			stopBtn.Disable()
+		}
+	}
+
+	// loadPages fetches pages until the range is exhausted, pages pages have
+	// been loaded (0 for all of them) or Stop is pressed, then calls done.
+	// The worker resumes from its own copy of next: query is only updated on
+	// the UI thread, which may not have caught up with the last page yet.
+	loadPages := func(pages int, done func()) {
+		busy = true
+		stop = make(chan struct{})
+		updateControls()
+
+		next, end, pattern := query.next, query.end, query.pattern
+		go func(stop chan struct{}) {
+			var err error
+			for loaded := 0; pages == 0 || loaded < pages; loaded++ {
+				var page serial.SearchPage
+				page, err = session.SearchPage(next, end, pattern, serial.DefaultSearchPageSize)
+				if err != nil {
+					break
+				}
+				next = page.Next
+				fyne.Do(func() {
+					hits = append(hits, page.Hits...)
+					query.more, query.next = page.More, page.Next
+					hitList.Refresh()
+					updateControls()
+				})
+				if !page.More {
+					break
+				}
+
+				select {
+				case <-stop:
+					err = serial.ErrStopped
+				default:
+				}
+				if err != nil {
+					break
+				}
+			}
+
+			fyne.Do(func() {
+				busy = false
+				updateControls()
+				switch {
+				case errors.Is(err, serial.ErrStopped):
+					output.SetText(fmt.Sprintf("Stopped - %d hit(s) loaded, Load More continues from 0x%08x", len(hits), query.next))
+				case err != nil:
+					output.SetText(fmt.Sprintf("Error: %v", err))
+				case done != nil:
+					done()
+				}
+			})
+		}(stop)
+	}
+
+	// countHits asks for the total once the first page shows there is more
+	// than one page. It scans the whole range, so it runs after that page
+	// rather than delaying it, and is dropped if another search has started.
+	countHits := func() {
+		gen := generation
+		start, end, pattern := query.start, query.end, query.pattern
+		go func() {
+			total, err := session.SearchCount(start, end, pattern)
+			fyne.Do(func() {
+				if gen != generation || err != nil {
+					return // Without a total the label keeps counting what has loaded
+				}
+				query.total = total
+				updateControls()
+			})
+		}()
+	}
+
+	searchBtn = widget.NewButton("Search Memory", func() {
+		if session.Port() == "" {
+			output.SetText("Error: Please enter a port name")
+			return
+		}
+
+		hexPattern, err := validateAndConvertPattern()
+		if err != nil {
+			output.SetText(fmt.Sprintf("Error: %v", err))
+			return
+		}
+
+		region := searchRegionSelect.Selected
+		updateInfoLabel()
+
+		searchRange(region, func(start, end uint32, err error) {
+			if err != nil {
+				output.SetText(fmt.Sprintf("Error: %v", err))
+				return
+			}
+
+			hits = nil
+			contexts = make(map[uint32]*format.MemoryBlock)
+			generation++
+			hitList.ScrollToTop()
+			hitList.Refresh()
+			query.start, query.end, query.pattern, query.next, query.more = start, end, hexPattern, start, false
+			query.total = -1
+			query.length = len(strings.SplitN(hexPattern, "/", 2)[0]) / 2
+
+			output.SetText(fmt.Sprintf("Searching %s, 0x%08x-0x%08x (%s)...", region, start, end, config.FormatSize(end-start)))
+			loadPages(1, func() {
+				if !query.more {
+					output.SetText(fmt.Sprintf("%d hit(s) in %s", len(hits), region))
+					return
+				}
+				output.SetText(fmt.Sprintf("First %d hit(s) in %s, Load More or Load All for the rest", len(hits), region))
+				countHits()
+			})
+		})
+	})
+	searchBtn.Importance = widget.HighImportance
+
+	loadMoreBtn = widget.NewButton("Load More", func() {
+		loadPages(1, nil)
+	})
+	loadAllBtn = widget.NewButton("Load All", func() {
+		loadPages(0, nil)
+	})
+	stopBtn = widget.NewButton("Stop", func() {
+		if stop != nil {
+			close(stop)
+			stop = nil
+		}
+		stopBtn.Disable()
+	})
+
+	// Export loads any remaining pages first so the file has every hit
+	exportBtn = widget.NewButton("Export Hits...", func() {
+		save := func() {
+			if len(hits) == 0 {
+				dialog.ShowInformation("Export Hits", "Nothing to export - run a search first", ctx.Window)
+				return
+			}
+			saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+				if err != nil || writer == nil {
+					return
+				}
+				defer writer.Close()
+				if err := format.WriteSearchHits(writer, hits, ctx.Symbols().Name); err != nil {
+					dialog.ShowError(err, ctx.Window)
+					return
+				}
+				output.SetText(fmt.Sprintf("Exported %d hit(s) to %s", len(hits), writer.URI().Path()))
+			}, ctx.Window)
+			saveDialog.SetFileName("search_hits.csv")
+			saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv"}))
+			saveDialog.Show()
+		}
+		if query.more {
+			output.SetText("Loading the remaining hits before exporting...")
+			loadPages(0, save)
+			return
+		}
+		save()
+	})
+
+	searchTypeRow := container.NewBorder(nil, nil, widget.NewLabel("Search Type:"), nil, searchTypeSelect)
+	searchPatternRow := container.NewBorder(nil, nil, widget.NewLabel("Pattern:"), nil, searchEntry)
+	searchRegionRow := container.NewBorder(nil, nil, widget.NewLabel("Region:"), nil, searchRegionSelect)
+
+	// Wrap search settings in a card with extra padding
+	searchSettingsCard := widget.NewCard("", "", container.NewPadded(container.NewVBox(
+		searchTypeRow,
+		searchPatternRow,
+		searchRegionRow,
+		rangeRow,
+		sectionRow,
+	)))
+
+	updateInfoLabel() // Set initial value
+	ctx.OnRegionsChanged(func() {
+		refreshRegions()
+		updateInfoLabel()
+	})
+
+	searchTab := container.NewVBox(
+		searchSettingsCard,
+		widget.NewCard("", "", container.NewPadded(infoLabel)),
+		container.NewBorder(nil, nil, nil, stopBtn, searchBtn),
+		container.NewBorder(nil, nil, nil, container.NewHBox(loadMoreBtn, loadAllBtn, exportBtn), countLabel),
+		withMinHeight(hitList, 200),
+	)
+	updateControls()
+
+	return container.NewTabItem("Search Memory", searchTab), refreshSections
+}
+
+// contextSegments renders a hit's context window as hex and ASCII, with the
+// matched bytes highlighted. block is nil while the window is being read.
+func contextSegments(block *format.MemoryBlock, hit uint32, length int) []widget.RichTextSegment {
+	plain := widget.RichTextStyle{Inline: true, TextStyle: fyne.TextStyle{Monospace: true}}
+	if block == nil {
+		return []widget.RichTextSegment{&widget.TextSegment{Text: "(reading...)", Style: plain}}
+	}
+	if len(block.Data) == 0 {
+		return []widget.RichTextSegment{&widget.TextSegment{Text: "(unreadable)", Style: plain}}
+	}
+
+	highlight := plain
+	highlight.ColorName = theme.ColorNamePrimary
+	highlight.TextStyle.Bold = true
+
+	// Split the window into before / match / after, for both columns
+	matchStart := min(int(hit-block.Address), len(block.Data))
+	matchEnd := min(matchStart+length, len(block.Data))
+	parts := [][]byte{block.Data[:matchStart], block.Data[matchStart:matchEnd], block.Data[matchEnd:]}
+
+	var segments []widget.RichTextSegment
+	add := func(text string, i int) {
+		style := plain
+		if i == 1 {
+			style = highlight
+		}
+		segments = append(segments, &widget.TextSegment{Text: text, Style: style})
+	}
+	for i, part := range parts {
+		var hex strings.Builder
+		for _, b := range part {
+			fmt.Fprintf(&hex, "%02x ", b)
+		}
+		add(hex.String(), i)
+	}
+	add(" ", 0)
+	for i, part := range parts {
+		ascii := make([]byte, len(part))
+		for j, b := range part {
+			ascii[j] = '.'
+			if b >= 0x20 && b < 0x7F {
+				ascii[j] = b
+			}
+		}
+		add(string(ascii), i)
+	}
+	return segments
+}
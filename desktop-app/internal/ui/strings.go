@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/config"
+	"github.com/MironCo/picopeeker/internal/format"
+	"github.com/MironCo/picopeeker/internal/serial"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// BuildStringsTab creates the Strings tab UI, which lists the printable text in
+// the last read or in a whole memory region, like `strings -t x`
+func BuildStringsTab(ctx *DeviceContext) *container.TabItem {
+	session, output, updateChan := ctx.Session, ctx.Output, ctx.UpdateChan
+
+	var allMatches, shown []format.StringMatch
+	var stop chan struct{}
+
+	sourceSelect := widget.NewSelect(nil, nil)
+	refreshSources := func() {
+		selected := sourceSelect.Selected
+		options := append([]string{"Last Read"}, regionNames(ctx.Regions())...)
+		sourceSelect.SetOptions(options)
+		if !slices.Contains(options, selected) {
+			selected = "Last Read"
+		}
+		sourceSelect.SetSelected(selected)
+	}
+	refreshSources()
+	ctx.OnRegionsChanged(refreshSources)
+
+	minLengthEntry := widget.NewEntry()
+	minLengthEntry.SetText(strconv.Itoa(format.DefaultMinStringLength))
+
+	filterEntry := widget.NewEntry()
+	filterEntry.SetPlaceHolder("Only show strings containing...")
+
+	progress := widget.NewProgressBar()
+	progress.Hide()
+
+	resultList := widget.NewList(
+		func() int { return len(shown) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			label.Truncation = fyne.TextTruncateEllipsis
+			return label
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			item.(*widget.Label).SetText(format.FormatStringMatch(shown[id]))
+		},
+	)
+	resultList.OnSelected = func(id widget.ListItemID) {
+		ctx.Navigate(shown[id].Address)
+		resultList.UnselectAll()
+	}
+
+	applyFilter := func() {
+		shown = format.FilterStrings(allMatches, filterEntry.Text)
+		resultList.ScrollToTop()
+		resultList.Refresh()
+	}
+	filterEntry.OnChanged = func(string) {
+		applyFilter()
+	}
+
+	var scanBtn, stopBtn *widget.Button
+	showResults := func(matches []format.StringMatch, source string) {
+		allMatches = matches
+		applyFilter()
+		progress.Hide()
+		scanBtn.Enable()
+		stopBtn.Disable()
+		output.SetText(fmt.Sprintf("%d string(s) found in %s - click one to open it in the Read tab", len(matches), source))
+	}
+
+	scanBtn = widget.NewButton("Find Strings", func() {
+		minLen, err := strconv.Atoi(minLengthEntry.Text)
+		if err != nil || minLen < 1 {
+			output.SetText("Error: Minimum length must be a positive number")
+			return
+		}
+
+		source := sourceSelect.Selected
+		if source == "Last Read" {
+			if len(ctx.LastRead.Data) == 0 {
+				output.SetText("Error: Read some memory first, or pick a region")
+				return
+			}
+			block := ctx.LastRead
+			showResults(format.ExtractStrings(block.Data, block.Address, minLen), fmt.Sprintf("the last read (0x%08x, %d bytes)", block.Address, len(block.Data)))
+			return
+		}
+
+		if session.Port() == "" {
+			output.SetText("Error: Please enter a port name")
+			return
+		}
+
+		region, ok := ctx.Regions().Find(source)
+		if !ok {
+			output.SetText(fmt.Sprintf("Error: %s is not in the memory map", source))
+			return
+		}
+		start, length := region.Start, int(region.Size())
+
+		stop = make(chan struct{})
+		progress.SetValue(0)
+		progress.Show()
+		scanBtn.Disable()
+		stopBtn.Enable()
+		output.SetText(fmt.Sprintf("Reading %s (%s)...", source, config.FormatSize(uint32(length))))
+
+		go func(stop chan struct{}) {
+			onProgress := func(done, total int) {
+				fyne.Do(func() {
+					progress.SetValue(float64(done) / float64(total))
+				})
+			}
+			block, err := session.ReadRegion(start, length, onProgress, stop)
+			matches := format.ExtractStrings(block.Data, block.Address, minLen)
+
+			description := fmt.Sprintf("%s (0x%08x-0x%08x)", source, start, start+uint32(len(block.Data)))
+			fyne.Do(func() {
+				showResults(matches, description)
+			})
+			switch {
+			case errors.Is(err, serial.ErrStopped):
+				updateChan <- UIUpdate{Text: fmt.Sprintf("Stopped - %d string(s) found in the first %d bytes of %s", len(matches), len(block.Data), source)}
+			case err != nil:
+				updateChan <- UIUpdate{Text: fmt.Sprintf("Error: %v\n%d string(s) found before the error", err, len(matches))}
+			}
+		}(stop)
+	})
+	scanBtn.Importance = widget.HighImportance
+
+	stopBtn = widget.NewButton("Stop", func() {
+		if stop != nil {
+			close(stop)
+			stop = nil
+		}
+		stopBtn.Disable()
+	})
+	stopBtn.Disable()
+
+	settingsCard := widget.NewCard("", "", container.NewPadded(container.NewVBox(
+		container.NewBorder(nil, nil, widget.NewLabel("Source:"), nil, sourceSelect),
+		container.NewBorder(nil, nil, widget.NewLabel("Minimum length:"), nil, minLengthEntry),
+		container.NewBorder(nil, nil, widget.NewLabel("Filter:"), nil, filterEntry),
+	)))
+
+	stringsTab := container.NewVBox(
+		settingsCard,
+		container.NewBorder(nil, nil, nil, stopBtn, scanBtn),
+		progress,
+		withMinHeight(resultList, 200),
+	)
+
+	return container.NewTabItem("Strings", stringsTab)
+}
@@ -0,0 +1,155 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/templates"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// buildTemplateCard creates the struct template controls for the Read tab.
+// address resolves the address currently entered in the tab and passes it to
+// done. The returned function saves a new template library, e.g. one from a
+// workspace.
+func buildTemplateCard(ctx *DeviceContext, address func(done func(uint32, error))) (fyne.CanvasObject, func(string) error) {
+	session, output, updateChan := ctx.Session, ctx.Output, ctx.UpdateChan
+
+	var library *templates.Library
+	templateSelect := widget.NewSelect(nil, nil)
+	templateSelect.PlaceHolder = "(no templates)"
+
+	setLibrary := func(lib *templates.Library) {
+		library = lib
+		selected := templateSelect.Selected
+		templateSelect.SetOptions(lib.Names())
+		if _, ok := lib.Find(selected); ok {
+			templateSelect.SetSelected(selected)
+		} else if len(lib.Structs) > 0 {
+			templateSelect.SetSelected(lib.Structs[0].Name)
+		} else {
+			templateSelect.ClearSelected()
+		}
+	}
+
+	source, err := templates.LoadSource()
+	if err != nil {
+		output.SetText(fmt.Sprintf("Error: %v", err))
+	}
+	if lib, err := templates.Parse(source); err == nil {
+		setLibrary(lib)
+	} else {
+		output.SetText(fmt.Sprintf("Error in template library: %v", err))
+	}
+
+	countEntry := widget.NewEntry()
+	countEntry.SetText("1")
+
+	applyBtn := widget.NewButton("Apply", func() {
+		if library == nil {
+			output.SetText("Error: Fix the template library with Edit... first")
+			return
+		}
+		s, ok := library.Find(templateSelect.Selected)
+		if !ok {
+			output.SetText("Error: Choose a template (or add one with Edit...)")
+			return
+		}
+		if session.Port() == "" {
+			output.SetText("Error: Please enter a port name")
+			return
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(countEntry.Text))
+		if err != nil || count < 1 || count > templates.MaxArrayRows {
+			output.SetText(fmt.Sprintf("Error: Count must be a number from 1 to %d", templates.MaxArrayRows))
+			return
+		}
+		if s.Size == 0 {
+			output.SetText(fmt.Sprintf("Error: %s has no fields", s.Name))
+			return
+		}
+
+		address(func(addr uint32, err error) {
+			if err != nil {
+				output.SetText(fmt.Sprintf("Error: %v", err))
+				return
+			}
+			regions := ctx.Regions()
+			if err := checkReadable(regions, addr); err != nil {
+				output.SetText(fmt.Sprintf("Error: %v", err))
+				return
+			}
+
+			// Only read the copies that fit before the end of the region
+			region, _ := regions.RegionAt(addr)
+			fit := int(min((uint64(region.End)-uint64(addr))/uint64(s.Size), uint64(count)))
+			if fit == 0 {
+				output.SetText(fmt.Sprintf("Error: %s (%d bytes) runs past the end of %s at 0x%08x", s.Name, s.Size, region.Name, region.End))
+				return
+			}
+			note := ""
+			if fit < count {
+				note = fmt.Sprintf("Only %d of %d fit before the end of %s at 0x%08x.\n\n", fit, count, region.Name, region.End)
+			}
+
+			output.SetText(fmt.Sprintf("Reading %d byte(s) for %s...", s.Size*fit, s.Name))
+			go func() {
+				block, err := session.ReadRegion(addr, s.Size*fit, nil, nil)
+				if err != nil {
+					updateChan <- UIUpdate{Text: fmt.Sprintf("Error: %v", err)}
+					return
+				}
+				updateChan <- UIUpdate{Text: note + templates.Render(s, block.Data, addr, fit)}
+			}()
+		})
+	})
+
+	editBtn := widget.NewButton("Edit...", func() {
+		source, _ := templates.LoadSource()
+		editor := widget.NewMultiLineEntry()
+		editor.TextStyle = fyne.TextStyle{Monospace: true}
+		editor.Wrapping = fyne.TextWrapOff
+		editor.SetText(source)
+
+		path, _ := templates.LibraryPath()
+		var editDialog *dialog.ConfirmDialog
+		editDialog = dialog.NewCustomConfirm("Struct Templates", "Save", "Cancel",
+			container.NewBorder(widget.NewLabel(path), nil, nil, nil, editor),
+			func(save bool) {
+				if !save {
+					return
+				}
+				lib, err := templates.SaveSource(editor.Text)
+				if err != nil {
+					// Keep the edits so the mistake can be fixed
+					editDialog.Show()
+					dialog.ShowError(err, ctx.Window)
+					return
+				}
+				setLibrary(lib)
+				output.SetText(fmt.Sprintf("Saved %d template(s) to %s", len(lib.Structs), path))
+			}, ctx.Window)
+		editDialog.Resize(fyne.NewSize(700, 600))
+		editDialog.Show()
+	})
+
+	// Count > 1 overlays an array of structs, e.g. a sprite table
+	countBox := container.NewGridWrap(fyne.NewSize(60, countEntry.MinSize().Height), countEntry)
+	controls := container.NewHBox(widget.NewLabel("Count:"), countBox, applyBtn, editBtn)
+	card := widget.NewCard("", "", container.NewPadded(
+		container.NewBorder(nil, nil, widget.NewLabel("Struct Template:"), controls, templateSelect),
+	))
+	setSource := func(source string) error {
+		lib, err := templates.SaveSource(source)
+		if err != nil {
+			return err
+		}
+		setLibrary(lib)
+		return nil
+	}
+	return card, setSource
+}
@@ -1,65 +1,119 @@
 package ui
 
 import (
+	"github.com/MironCo/picopeeker/internal/bookmarks"
 	"github.com/MironCo/picopeeker/internal/config"
+	"github.com/MironCo/picopeeker/internal/disasm"
+	"github.com/MironCo/picopeeker/internal/expr"
+	"github.com/MironCo/picopeeker/internal/firmware"
 	"github.com/MironCo/picopeeker/internal/format"
 	"github.com/MironCo/picopeeker/internal/serial"
+	"github.com/MironCo/picopeeker/internal/workspace"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"image/color"
+	"math"
+	"slices"
 	"strconv"
 	"strings"
+	"unicode"
 
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
 )
 
+// ReadTab is the Read Memory tab and what the rest of the window needs from it
+type ReadTab struct {
+	Item         *container.TabItem
+	ReadAt       func(addr uint32)         // Reads from addr, for other tabs to jump into the Read tab
+	Settings     func() workspace.Read     // The address, length and display format entered
+	Apply        func(workspace.Read)      // Restores saved settings
+	SetTemplates func(source string) error // Replaces the struct template library
+	Back         func()                    // Reads the previous location in the history
+	Forward      func()                    // Reads the next location after going back
+}
+
 // BuildReadMemoryTab creates the Read Memory tab UI
-func BuildReadMemoryTab(portEntry *widget.Entry, output *widget.Entry, updateChan chan UIUpdate, getModel func() config.PicoModel) *container.TabItem {
-	addressEntry := widget.NewEntry()
-	addressEntry.SetPlaceHolder("0x20000000")
+func BuildReadMemoryTab(ctx *DeviceContext) *ReadTab {
+	session, output, updateChan := ctx.Session, ctx.Output, ctx.UpdateChan
+
+	// The dropdown offers the addresses read recently
+	addressEntry := widget.NewSelectEntry(nil)
+	addressEntry.SetPlaceHolder("0x20000000, main+0x40 or *(0x20001000)+8")
 	addressEntry.SetText("0x20000000")
 
-	decrementBtn := widget.NewButton("-", func() {
-		address := addressEntry.Text
-		if address == "" {
+	lengthEntry := widget.NewEntry()
+	lengthEntry.SetPlaceHolder("256")
+	lengthEntry.SetText("256")
+
+	// The step buttons move by the read length, so consecutive reads line up
+	step := func(forward bool) {
+		if strings.TrimSpace(addressEntry.Text) == "" {
 			return
 		}
-		val := uint64(0)
-		fmt.Sscanf(address, "0x%x", &val)
-		if val >= 256 {
-			val -= 256
+		n, err := strconv.Atoi(strings.TrimSpace(lengthEntry.Text))
+		if err != nil || n <= 0 {
+			n = 256
 		}
-		addressEntry.SetText(fmt.Sprintf("0x%08x", val))
+		resolveAddress(ctx, addressEntry.Text, func(addr uint32, err error) {
+			if err != nil {
+				output.SetText(fmt.Sprintf("Error: %v", err))
+				return
+			}
+			switch {
+			case forward && addr <= math.MaxUint32-uint32(n):
+				addr += uint32(n)
+			case !forward && addr >= uint32(n):
+				addr -= uint32(n)
+			}
+			addressEntry.SetText(fmt.Sprintf("0x%08x", addr))
+		})
+	}
+	decrementBtn := widget.NewButton("-", func() {
+		step(false)
 	})
-
 	incrementBtn := widget.NewButton("+", func() {
-		address := addressEntry.Text
-		if address == "" {
-			return
-		}
-		val := uint64(0)
-		fmt.Sscanf(address, "0x%x", &val)
-		val += 256
-		addressEntry.SetText(fmt.Sprintf("0x%08x", val))
+		step(true)
 	})
 
-	lengthEntry := widget.NewEntry()
-	lengthEntry.SetPlaceHolder("256")
-	lengthEntry.SetText("256")
-
 	// Display format selector
-	displayFormatSelect := widget.NewSelect([]string{"Bytes (Hex)", "16-bit Words", "32-bit Words", "Float (32-bit)"}, nil)
-	displayFormatSelect.SetSelected("Bytes (Hex)")
+	displayFormatSelect := widget.NewSelect(format.DisplayFormats(), nil)
+	displayFormatSelect.SetSelected(format.DisplayBytes)
+
+	var history readHistory
+	var backBtn, forwardBtn *widget.Button
+	revisiting := false // Set while Back or Forward reads, so the read isn't recorded again
+	updateHistory := func() {
+		addressEntry.SetOptions(history.recentAddresses())
+		if history.canBack() {
+			backBtn.Enable()
+		} else {
+			backBtn.Disable()
+		}
+		if history.canForward() {
+			forwardBtn.Enable()
+		} else {
+			forwardBtn.Disable()
+		}
+	}
 
-	readMemoryBtn := widget.NewButton("Read Memory", func() {
-		portName := portEntry.Text
-		address := addressEntry.Text
+	var readMemoryBtn *widget.Button
+	readAt := func(address uint32) {
+		addressEntry.SetText(fmt.Sprintf("0x%08x", address))
+		readMemoryBtn.OnTapped()
+	}
+	readMemoryBtn = widget.NewButton("Read Memory", func() {
 		length := lengthEntry.Text
 
-		if portName == "" {
+		if session.Port() == "" {
 			output.SetText("Error: Please enter a port name")
 			return
 		}
-		if address == "" {
+		if strings.TrimSpace(addressEntry.Text) == "" {
 			output.SetText("Error: Please enter an address")
 			return
 		}
@@ -68,17 +122,6 @@ func BuildReadMemoryTab(portEntry *widget.Entry, output *widget.Entry, updateCha
 			return
 		}
 
-		// Validate address format (must be hex)
-		address = strings.TrimSpace(address)
-		if !strings.HasPrefix(address, "0x") && !strings.HasPrefix(address, "0X") {
-			output.SetText("Error: Address must start with 0x (e.g., 0x20000000)")
-			return
-		}
-		if _, err := strconv.ParseUint(address[2:], 16, 32); err != nil {
-			output.SetText("Error: Invalid hex address. Use format like 0x20000000")
-			return
-		}
-
 		// Validate length (must be a positive integer)
 		lengthVal, err := strconv.Atoi(length)
 		if err != nil || lengthVal <= 0 || lengthVal > 4096 {
@@ -86,37 +129,112 @@ func BuildReadMemoryTab(portEntry *widget.Entry, output *widget.Entry, updateCha
 			return
 		}
 
-		output.SetText("Reading memory...")
-
-		// Run read in background goroutine to keep UI responsive
-		go func() {
-			result, err := serial.ReadMemory(portName, address, length)
+		// The address may be an expression, e.g. main+0x40 or *(0x20001000)+8
+		resolveAddress(ctx, addressEntry.Text, func(addr uint32, err error) {
 			if err != nil {
-				updateChan <- UIUpdate{Text: fmt.Sprintf("Error: %v", err)}
-			} else {
-				// Format output based on selected display mode
-				displayMode := displayFormatSelect.Selected
-				formatted := format.FormatMemoryDump(result, displayMode)
-				updateChan <- UIUpdate{Text: formatted}
+				output.SetText(fmt.Sprintf("Error: %v", err))
+				return
+			}
+			if err := checkReadable(ctx.Regions(), addr); err != nil {
+				output.SetText(fmt.Sprintf("Error: %v", err))
+				return
+			}
+			address := fmt.Sprintf("0x%08x", addr)
+			if !revisiting {
+				history.visit(workspace.Read{Address: address, Length: length, Display: displayFormatSelect.Selected})
+				updateHistory()
 			}
-		}()
+
+			output.SetText("Reading memory...")
+
+			// Run read in background goroutine to keep UI responsive
+			go func() {
+				result, err := session.ReadMemory(address, length)
+				if err != nil {
+					updateChan <- UIUpdate{Text: fmt.Sprintf("Error: %v", err)}
+				} else {
+					// Keep the raw bytes around for export
+					block := format.ParseMemoryBlock(result)
+					fyne.Do(func() {
+						ctx.LastRead = block
+					})
+
+					// Format output based on selected display mode
+					displayMode := displayFormatSelect.Selected
+					if displayMode == format.DisplayBytes && len(block.Data) > 0 {
+						fyne.Do(func() {
+							ctx.HexView.ShowBlock(block)
+						})
+						return
+					}
+					if displayMode == format.DisplayThumb || displayMode == format.DisplayRISCV {
+						showDisassembly(ctx, block, displayMode, readAt)
+						return
+					}
+					formatted := format.FormatMemoryDump(result, displayMode)
+					updateChan <- UIUpdate{Text: formatted}
+				}
+			}()
+		})
 	})
 	readMemoryBtn.Importance = widget.HighImportance
 
-	romBtn := widget.NewButton("ROM", func() {
-		addressEntry.SetText("0x00000000")
-	})
-	flashBtn := widget.NewButton("Flash", func() {
-		addressEntry.SetText("0x10000000")
-	})
-	sramBtn := widget.NewButton("SRAM", func() {
-		addressEntry.SetText("0x20000000")
+	// revisit reads a location from the history without recording it again
+	revisit := func(loc workspace.Read, ok bool) {
+		if !ok {
+			return
+		}
+		addressEntry.SetText(loc.Address)
+		lengthEntry.SetText(loc.Length)
+		displayFormatSelect.SetSelected(loc.Display)
+		updateHistory()
+		revisiting = true
+		readMemoryBtn.OnTapped()
+		revisiting = false
+	}
+	back := func() {
+		revisit(history.back())
+	}
+	forward := func() {
+		revisit(history.forward())
+	}
+	backBtn = widget.NewButtonWithIcon("", theme.NavigateBackIcon(), back)
+	forwardBtn = widget.NewButtonWithIcon("", theme.NavigateNextIcon(), forward)
+	updateHistory()
+
+	exportBtn := widget.NewButton("Export...", func() {
+		ShowExportDialog(ctx.Window, ctx.LastRead, func(message string) {
+			output.SetText(message)
+		})
 	})
+
+	// Quick access follows the memory map, so custom boards get their own
+	// regions. Aliases are left to the search and strings pickers.
 	gpioBtn := widget.NewButton("GPIO", func() {
 		addressEntry.SetText("0x40028000")
 	})
+	quickAccess := container.NewHBox()
+	refreshQuickAccess := func() {
+		quickAccess.RemoveAll()
+		quickAccess.Add(widget.NewLabel("Quick Access:"))
+		for _, region := range ctx.Regions().Searchable() {
+			if region.AliasOf != "" {
+				continue
+			}
+			quickAccess.Add(widget.NewButton(region.Name, func() {
+				addressEntry.SetText(fmt.Sprintf("0x%08x", region.Start))
+			}))
+		}
+		quickAccess.Add(gpioBtn)
+	}
+	refreshQuickAccess()
+	ctx.OnRegionsChanged(refreshQuickAccess)
 
-	addressRow := container.NewBorder(nil, nil, widget.NewLabel("Address:"), container.NewHBox(decrementBtn, incrementBtn), addressEntry)
+	templateCard, setTemplates := buildTemplateCard(ctx, func(done func(uint32, error)) {
+		resolveAddress(ctx, addressEntry.Text, done)
+	})
+
+	addressRow := container.NewBorder(nil, nil, container.NewHBox(backBtn, forwardBtn, widget.NewLabel("Address:")), container.NewHBox(decrementBtn, incrementBtn), addressEntry)
 	lengthRow := container.NewBorder(nil, nil, widget.NewLabel("Length:"), nil, lengthEntry)
 	displayFormatRow := container.NewBorder(nil, nil, widget.NewLabel("Display as:"), nil, displayFormatSelect)
 
@@ -129,141 +247,249 @@ func BuildReadMemoryTab(portEntry *widget.Entry, output *widget.Entry, updateCha
 
 	readTab := container.NewVBox(
 		memorySettingsCard,
-		widget.NewCard("", "", container.NewPadded(
-			container.NewHBox(widget.NewLabel("Quick Access:"), romBtn, flashBtn, sramBtn, gpioBtn),
-		)),
-		readMemoryBtn,
+		widget.NewCard("", "", container.NewPadded(quickAccess)),
+		container.NewBorder(nil, nil, nil, exportBtn, readMemoryBtn),
+		templateCard,
 	)
 
-	return container.NewTabItem("Read Memory", readTab)
+	return &ReadTab{
+		Item:   container.NewTabItem("Read Memory", readTab),
+		ReadAt: readAt,
+		Settings: func() workspace.Read {
+			return workspace.Read{Address: addressEntry.Text, Length: lengthEntry.Text, Display: displayFormatSelect.Selected}
+		},
+		Apply: func(settings workspace.Read) {
+			if settings.Address != "" {
+				addressEntry.SetText(settings.Address)
+			}
+			if settings.Length != "" {
+				lengthEntry.SetText(settings.Length)
+			}
+			if slices.Contains(format.DisplayFormats(), settings.Display) {
+				displayFormatSelect.SetSelected(settings.Display)
+			}
+		},
+		SetTemplates: setTemplates,
+		Back:         back,
+		Forward:      forward,
+	}
 }
 
-// BuildSearchMemoryTab creates the Search Memory tab UI
-func BuildSearchMemoryTab(portEntry *widget.Entry, output *widget.Entry, updateChan chan UIUpdate, getModel func() config.PicoModel) *container.TabItem {
-	searchEntry := widget.NewEntry()
-	searchEntry.SetPlaceHolder("Enter pattern...")
-	searchEntry.SetText("")
-
-	searchTypeSelect := widget.NewSelect([]string{"Hex Bytes", "ASCII String", "32-bit Int (LE)"}, func(value string) {
-		switch value {
-		case "Hex Bytes":
-			searchEntry.SetPlaceHolder("DEADBEEF")
-		case "ASCII String":
-			searchEntry.SetPlaceHolder("hello world")
-		case "32-bit Int (LE)":
-			searchEntry.SetPlaceHolder("42")
-		}
+// showDisassembly decodes a block read from the device and shows the listing,
+// annotated with symbols if an ELF is loaded. navigate is called with the
+// address of a tapped branch target.
+func showDisassembly(ctx *DeviceContext, block format.MemoryBlock, displayMode string, navigate func(uint32)) {
+	data, addr := block.Data, block.Address
+
+	// Both instruction sets are halfword aligned
+	if addr%2 != 0 && len(data) > 0 {
+		data, addr = data[1:], addr+1
+	}
+
+	var insts []disasm.Instruction
+	var title string
+	arch := config.ArchARM
+	if displayMode == format.DisplayRISCV {
+		arch = config.ArchRISCV
+		insts = disasm.DecodeRISCV(data, addr)
+		title = fmt.Sprintf("RISC-V disassembly at 0x%08x (%d bytes)", addr, len(data))
+	} else {
+		insts = disasm.DecodeThumb(data, addr)
+		title = fmt.Sprintf("Thumb disassembly at 0x%08x (%d bytes)", addr, len(data))
+	}
+
+	// Flash and SRAM hold code for the architecture the cores are running,
+	// but the RP2350 boot ROM has both, so decoding either way is allowed
+	if ctx.Arch() != arch {
+		title += fmt.Sprintf(" - note: cores are set to %s", config.GetArchString(ctx.Arch()))
+	}
+
+	var symbolize disasm.Symbolizer
+	if symbols := ctx.Symbols(); symbols.Len() > 0 {
+		symbolize = symbols.Name
+	}
+
+	fyne.Do(func() {
+		ctx.Disassembly.Show(title, insts, symbolize, navigate)
 	})
-	searchTypeSelect.SetSelected("Hex Bytes")
+}
 
-	searchRegionSelect := widget.NewSelect([]string{"SRAM", "Flash"}, nil)
-	searchRegionSelect.SetSelected("SRAM")
+// resolveAddress evaluates an address expression and calls done with the
+// result on the UI thread. Names are looked up right away, but expressions
+// that dereference memory finish in the background since they read from the device.
+func resolveAddress(ctx *DeviceContext, text string, done func(addr uint32, err error)) {
+	if strings.TrimSpace(text) == "" {
+		done(0, fmt.Errorf("Enter an address (e.g., 0x20000000), a symbol or an expression"))
+		return
+	}
 
-	// Helper function to validate and convert search pattern
-	validateAndConvertPattern := func() (string, error) {
-		pattern := searchEntry.Text
-		searchType := searchTypeSelect.Selected
+	env := addressEnv(ctx)
+	read := env.Read
+	env.Read = nil
+	addr, err := expr.Eval(text, env)
+	if !errors.Is(err, expr.ErrNoDevice) {
+		done(addr, addressError(ctx, text, err))
+		return
+	}
+	if ctx.Session.Port() == "" {
+		done(0, fmt.Errorf("%q reads from the device (*) - please enter a port name", text))
+		return
+	}
 
-		if pattern == "" {
-			return "", fmt.Errorf("Please enter a search pattern")
-		}
+	env.Read = read
+	go func() {
+		addr, err := expr.Eval(text, env)
+		fyne.Do(func() {
+			done(addr, addressError(ctx, text, err))
+		})
+	}()
+}
 
-		// Convert pattern to hex based on type
-		hexPattern := ""
-		switch searchType {
-		case "Hex Bytes":
-			// Validate hex bytes - remove spaces and check if valid hex
-			hexPattern = strings.ReplaceAll(strings.ToUpper(pattern), " ", "")
-			if len(hexPattern)%2 != 0 {
-				return "", fmt.Errorf("Hex pattern must have even number of characters (e.g., DEADBEEF)")
-			}
-			if len(hexPattern) == 0 {
-				return "", fmt.Errorf("Hex pattern cannot be empty")
-			}
-			// Check if all characters are valid hex
-			for _, c := range hexPattern {
-				if !((c >= '0' && c <= '9') || (c >= 'A' && c <= 'F')) {
-					return "", fmt.Errorf("Invalid hex pattern. Use only 0-9 and A-F (e.g., DEADBEEF)")
-				}
-			}
-		case "ASCII String":
-			if pattern == "" {
-				return "", fmt.Errorf("ASCII string cannot be empty")
-			}
-			hexPattern = format.StringToHex(pattern)
-		case "32-bit Int (LE)":
-			val, err := strconv.ParseInt(pattern, 10, 32)
-			if err != nil {
-				return "", fmt.Errorf("Invalid integer: %v", err)
-			}
-			hexPattern = format.Int32ToHexLE(int32(val))
-		}
-		return hexPattern, nil
+// addressError explains why an address expression couldn't be evaluated
+func addressError(ctx *DeviceContext, text string, err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, expr.ErrUnknownName) && ctx.Symbols().Len() == 0:
+		return fmt.Errorf("Invalid address %q: %v (load the firmware's ELF with Load ELF... for symbols)", text, err)
+	default:
+		return fmt.Errorf("Invalid address %q: %v", text, err)
 	}
+}
 
-	searchBtn := widget.NewButton("Search Memory", func() {
-		portName := portEntry.Text
-		if portName == "" {
-			output.SetText("Error: Please enter a port name")
-			return
-		}
+// addressEnv gives names in address expressions their values: ELF symbols,
+// bookmarks, the firmware's landmarks and memory regions (sram, sram_end,
+// flash_size...). It copies the bookmarks, so build it on the UI thread.
+func addressEnv(ctx *DeviceContext) expr.Env {
+	symbols, regions, session := ctx.Symbols(), ctx.Regions(), ctx.Session
+	var marks []bookmarks.Bookmark
+	if ctx.Bookmarks != nil {
+		marks = ctx.Bookmarks.Bookmarks()
+	}
+	var landmarks string
+	if ctx.Landmarks != nil {
+		landmarks = ctx.Landmarks()
+	}
 
-		hexPattern, err := validateAndConvertPattern()
+	lookup := func(name string) (uint32, bool) {
+		if sym, ok := symbols.Find(name); ok {
+			return sym.Address, true
+		}
+		for _, b := range marks {
+			if b.Name == name {
+				return b.Address, true
+			}
+		}
+		if addr, ok := serial.LandmarkAddress(landmarks, name); ok {
+			return addr, true
+		}
+		return regionValue(regions, name)
+	}
+	read := func(addr uint32) (uint32, error) {
+		block, err := session.ReadBlock(addr, 4)
 		if err != nil {
-			output.SetText(fmt.Sprintf("Error: %v", err))
-			return
+			return 0, err
 		}
+		if len(block.Data) < 4 {
+			return 0, fmt.Errorf("got %d of 4 bytes", len(block.Data))
+		}
+		return binary.LittleEndian.Uint32(block.Data), nil
+	}
+	return expr.Env{Lookup: lookup, Read: read}
+}
 
-		region := searchRegionSelect.Selected
-
-		// Run search in background goroutine to keep UI responsive
-		go func() {
-			var result string
-			var err error
+// regionValue looks up a region name in an expression. Names are lower case
+// with underscores for other characters, e.g. "flash_uncached" for
+// "Flash (uncached)", and take a _start, _end (exclusive) or _size suffix.
+func regionValue(regions config.MemoryRegions, name string) (uint32, bool) {
+	name = strings.ToLower(name)
+	for _, region := range regions.Map {
+		key := regionKey(region.Name)
+		switch name {
+		case key, key + "_start":
+			return region.Start, true
+		case key + "_end":
+			return region.End, true
+		case key + "_size":
+			return region.Size(), true
+		}
+	}
+	return 0, false
+}
 
-			if region == "SRAM" {
-				updateChan <- UIUpdate{Text: "Searching SRAM (this may take 5-10 seconds)..."}
-				result, err = serial.SearchMemory(portName, hexPattern)
-			} else { // Flash
-				updateChan <- UIUpdate{Text: "Searching Flash (this may take 30-60 seconds for 4MB)..."}
-				result, err = serial.SearchFlash(portName, hexPattern)
-			}
+func regionKey(name string) string {
+	var key strings.Builder
+	for _, c := range strings.ToLower(name) {
+		switch {
+		case unicode.IsLetter(c) || unicode.IsDigit(c):
+			key.WriteRune(c)
+		case key.Len() > 0 && !strings.HasSuffix(key.String(), "_"):
+			key.WriteByte('_')
+		}
+	}
+	return strings.TrimSuffix(key.String(), "_")
+}
 
-			if err != nil {
-				updateChan <- UIUpdate{Text: fmt.Sprintf("Error: %v", err)}
-			} else {
-				updateChan <- UIUpdate{Text: result}
-			}
-		}()
-	})
-	searchBtn.Importance = widget.HighImportance
+// withMinHeight gives lists a usable height inside the tabs' VBox layouts
+func withMinHeight(obj fyne.CanvasObject, height float32) fyne.CanvasObject {
+	spacer := canvas.NewRectangle(color.Transparent)
+	spacer.SetMinSize(fyne.NewSize(0, height))
+	return container.NewStack(spacer, obj)
+}
 
-	searchTypeRow := container.NewBorder(nil, nil, widget.NewLabel("Search Type:"), nil, searchTypeSelect)
-	searchPatternRow := container.NewBorder(nil, nil, widget.NewLabel("Pattern:"), nil, searchEntry)
-	searchRegionRow := container.NewBorder(nil, nil, widget.NewLabel("Region:"), nil, searchRegionSelect)
+// DeviceContext is what a device window shares with its tabs
+type DeviceContext struct {
+	Window      fyne.Window
+	Session     *serial.Session
+	Output      *widget.Entry
+	UpdateChan  chan UIUpdate
+	Regions     func() config.MemoryRegions
+	Arch        func() config.CoreArch
+	Symbols     func() *firmware.SymbolTable // Symbols of the loaded ELF, nil if none
+	Disassembly *DisassemblyView
+	HexView     *HexView
+	Bookmarks   *BookmarkPanel
+	Landmarks   func() string      // Landmarks reported by the firmware, as returned by serial.ParseLandmarks
+	Navigate    func(addr uint32)  // Switches to the Read tab and reads from addr
+	LastRead    format.MemoryBlock // Last block read in the Read tab (UI thread only)
+
+	regionListeners []func()
+}
 
-	// Wrap search settings in a card with extra padding
-	searchSettingsCard := widget.NewCard("", "", container.NewPadded(container.NewVBox(
-		searchTypeRow,
-		searchPatternRow,
-		searchRegionRow,
-	)))
+// OnRegionsChanged registers f to run on the UI thread whenever the memory map changes
+func (ctx *DeviceContext) OnRegionsChanged(f func()) {
+	ctx.regionListeners = append(ctx.regionListeners, f)
+}
 
-	// Info label that updates based on selected model
-	infoLabel := widget.NewLabel("")
-	updateInfoLabel := func() {
-		regions := config.GetMemoryRegions(getModel())
-		infoLabel.SetText(fmt.Sprintf("SRAM: Runtime data (%s) | Flash: String literals (%s)", regions.SRAMSize, regions.FlashSize))
+// RegionsChanged tells the tabs that the memory map changed, e.g. after
+// connecting or choosing another board
+func (ctx *DeviceContext) RegionsChanged() {
+	for _, f := range ctx.regionListeners {
+		f()
 	}
-	updateInfoLabel() // Set initial value
+}
 
-	searchTab := container.NewVBox(
-		searchSettingsCard,
-		widget.NewCard("", "", container.NewPadded(infoLabel)),
-		searchBtn,
-	)
+// regionNames lists the regions offered for quick access, searches and scans
+func regionNames(regions config.MemoryRegions) []string {
+	var names []string
+	for _, region := range regions.Searchable() {
+		names = append(names, region.Name)
+	}
+	return names
+}
 
-	return container.NewTabItem("Search Memory", searchTab)
+// checkReadable returns an error if addr is outside the memory map
+func checkReadable(regions config.MemoryRegions, addr uint32) error {
+	if region, ok := regions.RegionAt(addr); ok && region.Access&config.AccessRead != 0 {
+		return nil
+	}
+	var ranges []string
+	for _, region := range regions.Map {
+		if region.Access&config.AccessRead != 0 {
+			ranges = append(ranges, fmt.Sprintf("%s 0x%08x-0x%08x", region.Name, region.Start, region.End-1))
+		}
+	}
+	return fmt.Errorf("0x%08x is not in a readable region. Valid ranges: %s", addr, strings.Join(ranges, ", "))
 }
 
 // UIUpdate is a message type for updating the UI from background goroutines
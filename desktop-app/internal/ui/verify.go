@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"github.com/MironCo/picopeeker/internal/config"
+	"github.com/MironCo/picopeeker/internal/firmware"
+	"github.com/MironCo/picopeeker/internal/format"
+	"fmt"
+	"io"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// verifyChunkSize matches the firmware's default PICOPEEKER_MAX_READ_SIZE
+const verifyChunkSize = 4096
+
+// BuildVerifyTab creates the Verify Flash tab UI, which compares flash with a UF2 or ELF image
+func BuildVerifyTab(ctx *DeviceContext) *container.TabItem {
+	window, session, output, updateChan, getRegions := ctx.Window, ctx.Session, ctx.Output, ctx.UpdateChan, ctx.Regions
+
+	var imageName string
+	var imageBlocks []format.MemoryBlock
+	var results []firmware.ChunkResult
+
+	imageLabel := widget.NewLabel("No image loaded")
+	progress := widget.NewProgressBar()
+	progress.Hide()
+
+	resultList := widget.NewList(
+		func() int { return len(results) },
+		func() fyne.CanvasObject {
+			label := widget.NewLabel("")
+			label.TextStyle = fyne.TextStyle{Monospace: true}
+			return label
+		},
+		func(id widget.ListItemID, item fyne.CanvasObject) {
+			label := item.(*widget.Label)
+			label.SetText(results[id].Summary())
+			if results[id].Match() {
+				label.Importance = widget.SuccessImportance
+			} else {
+				label.Importance = widget.DangerImportance
+			}
+			label.Refresh()
+		},
+	)
+	resultList.OnSelected = func(id widget.ListItemID) {
+		result := results[id]
+		switch {
+		case result.Err != nil:
+			output.SetText(fmt.Sprintf("Error reading 0x%08x: %v", result.Address, result.Err))
+		case result.Diffs == 0:
+			output.SetText(fmt.Sprintf("0x%08x: %d bytes match the image", result.Address, len(result.Expected)))
+		default:
+			output.SetText(format.FormatHexDiff(result.Expected, result.Actual, result.Address))
+		}
+	}
+
+	openBtn := widget.NewButton("Open UF2/ELF...", func() {
+		openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+
+			data, err := io.ReadAll(reader)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+			blocks, err := firmware.LoadImage(reader.URI().Name(), data)
+			if err != nil {
+				dialog.ShowError(err, window)
+				return
+			}
+
+			imageName = reader.URI().Name()
+			imageBlocks = blocks
+			total := 0
+			for _, block := range blocks {
+				total += len(block.Data)
+			}
+			imageLabel.SetText(fmt.Sprintf("%s: %d segment(s), %d bytes", imageName, len(blocks), total))
+		}, window)
+		openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".uf2", ".elf", ".axf"}))
+		openDialog.Show()
+	})
+
+	var verifyBtn *widget.Button
+	verifyBtn = widget.NewButton("Verify Flash", func() {
+		if imageBlocks == nil {
+			output.SetText("Error: Open a UF2 or ELF image first")
+			return
+		}
+		if session.Port() == "" {
+			output.SetText("Error: Please enter a port name")
+			return
+		}
+
+		// Only flash can be compared; RAM segments and the RP2350 workaround block are skipped
+		regions := getRegions()
+		flashBlocks := firmware.FilterRange(imageBlocks, config.FlashStart, config.FlashStart+regions.FlashSizeHex)
+		if len(flashBlocks) == 0 {
+			output.SetText(fmt.Sprintf("Error: %s has nothing in flash (0x%08x-0x%08x)", imageName, config.FlashStart, config.FlashStart+regions.FlashSizeHex-1))
+			return
+		}
+
+		results = nil
+		resultList.UnselectAll()
+		resultList.Refresh()
+		progress.SetValue(0)
+		progress.Show()
+		verifyBtn.Disable()
+		output.SetText(fmt.Sprintf("Verifying %s against flash...", imageName))
+
+		go func() {
+			read := func(address uint32, length int) ([]byte, error) {
+				block, err := session.ReadBlock(address, length)
+				return block.Data, err
+			}
+			onProgress := func(done, total int) {
+				fyne.Do(func() {
+					progress.SetValue(float64(done) / float64(total))
+				})
+			}
+			found := firmware.Verify(flashBlocks, verifyChunkSize, read, onProgress)
+
+			matched, mismatched, failed := 0, 0, 0
+			for _, result := range found {
+				switch {
+				case result.Err != nil:
+					failed++
+				case result.Diffs == 0:
+					matched++
+				default:
+					mismatched++
+				}
+			}
+
+			fyne.Do(func() {
+				results = found
+				resultList.Refresh()
+				progress.Hide()
+				verifyBtn.Enable()
+			})
+
+			verdict := "Flash MATCHES the image"
+			if mismatched > 0 || failed > 0 {
+				verdict = "Flash DOES NOT match the image - select a block to see the differences"
+			}
+			updateChan <- UIUpdate{Text: fmt.Sprintf("%s\n%d block(s) match, %d mismatch, %d could not be read",
+				verdict, matched, mismatched, failed)}
+		}()
+	})
+	verifyBtn.Importance = widget.HighImportance
+
+	imageCard := widget.NewCard("", "", container.NewPadded(
+		container.NewBorder(nil, nil, nil, openBtn, imageLabel),
+	))
+
+	verifyTab := container.NewVBox(
+		imageCard,
+		verifyBtn,
+		progress,
+		withMinHeight(resultList, 200),
+	)
+
+	return container.NewTabItem("Verify Flash", verifyTab)
+}
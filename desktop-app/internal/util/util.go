@@ -1,12 +1,125 @@
 package util
 
-import "path/filepath"
+import (
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
-// findUSBModemPort scans for /dev/tty.usbmodem* devices
+	"go.bug.st/serial/enumerator"
+)
+
+// RaspberryPiVID is the USB vendor ID used by Raspberry Pi boards
+const RaspberryPiVID = "2E8A"
+
+// knownPIDs maps USB product IDs to the chip behind them
+var knownPIDs = map[string]string{
+	"000A": "RP2040", // Pico SDK stdio_usb CDC (RP2040)
+	"0009": "RP2350", // Pico SDK stdio_usb CDC (RP2350)
+	"000C": "",       // Debug Probe UART bridge, target chip unknown
+}
+
+// PortInfo describes a USB serial port that belongs to a Raspberry Pi board
+type PortInfo struct {
+	Name         string // OS device name, e.g. /dev/ttyACM0, /dev/cu.usbmodem2101, COM3
+	VID          string
+	PID          string
+	SerialNumber string // Pico SDK firmware reports the unique board ID here
+	Product      string
+	Chip         string // Guessed from the PID, empty if unknown
+}
+
+// FindPicoPorts lists the serial ports that belong to Raspberry Pi boards
+func FindPicoPorts() ([]PortInfo, error) {
+	ports, err := enumerator.GetDetailedPortsList()
+	if err != nil {
+		return nil, err
+	}
+
+	result := []PortInfo{}
+	for _, port := range ports {
+		if !port.IsUSB || !strings.EqualFold(port.VID, RaspberryPiVID) {
+			continue
+		}
+
+		pid := strings.ToUpper(port.PID)
+		chip, known := knownPIDs[pid]
+		if !known {
+			continue
+		}
+
+		result = append(result, PortInfo{
+			Name:         port.Name,
+			VID:          strings.ToUpper(port.VID),
+			PID:          pid,
+			SerialNumber: port.SerialNumber,
+			Product:      port.Product,
+			Chip:         chip,
+		})
+	}
+
+	slices.SortFunc(result, func(a, b PortInfo) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return result, nil
+}
+
+// FindUSBModemPort returns the first Pico serial port, or "" if none is attached
 func FindUSBModemPort() string {
-	matches, err := filepath.Glob("/dev/tty.usbmodem*")
-	if err != nil || len(matches) == 0 {
-		return "/dev/tty.usbmodem2101" // fallback default
+	ports, err := FindPicoPorts()
+	if err != nil || len(ports) == 0 {
+		return ""
+	}
+	return ports[0].Name
+}
+
+// FindPortBySerial returns the port currently used by the board with the given
+// USB serial number. Useful when a board re-enumerates under a different name.
+func FindPortBySerial(serialNumber string) (PortInfo, bool) {
+	if serialNumber == "" {
+		return PortInfo{}, false
+	}
+
+	ports, err := FindPicoPorts()
+	if err != nil {
+		return PortInfo{}, false
+	}
+	for _, port := range ports {
+		if port.SerialNumber == serialNumber {
+			return port, true
+		}
+	}
+	return PortInfo{}, false
+}
+
+// WatchPicoPorts polls for Pico serial ports and calls onChange (from a background
+// goroutine) with the new list whenever a board is plugged in or removed.
+// The returned function stops the watcher.
+func WatchPicoPorts(interval time.Duration, onChange func([]PortInfo)) (stop func()) {
+	done := make(chan struct{})
+
+	go func() {
+		var last []PortInfo
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			ports, err := FindPicoPorts()
+			if err == nil && !slices.Equal(ports, last) {
+				last = ports
+				onChange(ports)
+			}
+
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
 	}
-	return matches[0] // return first match
 }
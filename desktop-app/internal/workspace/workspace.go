@@ -0,0 +1,93 @@
+package workspace
+
+import (
+	"github.com/MironCo/picopeeker/internal/bookmarks"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+)
+
+// Extension is used for workspace files
+const Extension = ".picopeeker"
+
+// MaxRecent is how many recently used workspaces are remembered
+const MaxRecent = 8
+
+// Read is the state of the Read tab
+type Read struct {
+	Address string `json:"address,omitempty"`
+	Length  string `json:"length,omitempty"`
+	Display string `json:"display,omitempty"`
+}
+
+// Layout is the state of a device window
+type Layout struct {
+	Width         float32 `json:"width,omitempty"`
+	Height        float32 `json:"height,omitempty"`
+	Tab           string  `json:"tab,omitempty"`
+	ShowBookmarks bool    `json:"show_bookmarks,omitempty"`
+	HideInspector bool    `json:"hide_inspector,omitempty"`
+}
+
+// Workspace is everything needed to pick up work on a firmware project where
+// it was left: the ELF, the device settings, bookmarks, struct templates and
+// the window layout
+type Workspace struct {
+	ELF       string               `json:"elf,omitempty"` // Path of the firmware's ELF
+	Port      string               `json:"port,omitempty"`
+	Model     string               `json:"model,omitempty"`
+	Read      Read                 `json:"read"`
+	Bookmarks []bookmarks.Bookmark `json:"bookmarks,omitempty"`
+	Templates string               `json:"templates,omitempty"` // Struct template library source
+	Layout    Layout               `json:"layout"`
+}
+
+// Load reads a workspace file
+func Load(path string) (*Workspace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read workspace: %w", err)
+	}
+	var ws Workspace
+	if err := json.Unmarshal(data, &ws); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return &ws, nil
+}
+
+// Write writes the workspace as indented JSON
+func (ws *Workspace) Write(w io.Writer) error {
+	data, err := json.MarshalIndent(ws, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// Save writes the workspace to path
+func (ws *Workspace) Save(path string) error {
+	var buf bytes.Buffer
+	if err := ws.Write(&buf); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to save workspace: %w", err)
+	}
+	return nil
+}
+
+// AddRecent moves path to the front of the recently used list
+func AddRecent(recent []string, path string) []string {
+	recent = slices.DeleteFunc(slices.Clone(recent), func(other string) bool {
+		return other == path
+	})
+	recent = append([]string{path}, recent...)
+	if len(recent) > MaxRecent {
+		recent = recent[:MaxRecent]
+	}
+	return recent
+}
@@ -22,6 +22,7 @@
  *   SEARCH:HEXPATTERN       - Search SRAM for pattern
  *   SEARCHFLASH:HEXPATTERN  - Search Flash for pattern
  *   LANDMARKS               - Show memory landmarks
+ *   INFO                    - Identify the chip, SDK and memory sizes
  *
  * NOTE: Reads memory while app is running - may see transient values during updates.
  *       This is normal for a debugging tool. Flash is always safe (read-only).
@@ -39,6 +40,15 @@
 #include <stdint.h>
 #include "pico/stdlib.h"
 #include "pico/multicore.h"
+#include "pico/version.h"
+#include "hardware/structs/sysinfo.h"
+#if LIB_PICO_UNIQUE_ID
+#include "pico/unique_id.h"
+#endif
+#if LIB_PICO_FLASH && LIB_HARDWARE_FLASH
+#include "pico/flash.h"
+#include "hardware/flash.h"
+#endif
 
 #ifdef __cplusplus
 extern "C" {
@@ -46,7 +56,7 @@ extern "C" {
 
 // Configuration
 #ifndef PICOPEEKER_CMD_BUFFER_SIZE
-#define PICOPEEKER_CMD_BUFFER_SIZE 128
+#define PICOPEEKER_CMD_BUFFER_SIZE 320  // Room for a full-size pattern plus its mask
 #endif
 
 #ifndef PICOPEEKER_MAX_PATTERN_SIZE
@@ -65,26 +75,105 @@ extern "C" {
 #define PICOPEEKER_LED_PIN 25  // Default onboard LED
 #endif
 
-// Memory regions - automatically configured for RP2040 (Pico 1) or RP2350 (Pico 2)
+// Memory regions - automatically configured for RP2040 (Pico 1) or RP2350 (Pico 2).
+// Custom boards can override PICOPEEKER_FLASH_END and PICOPEEKER_SRAM_END, and
+// add regions (listed by INFO for the GUI) with PICOPEEKER_EXTRA_REGIONS, e.g.
+//   #define PICOPEEKER_FLASH_END 0x11000000  // 16MB
+//   #define PICOPEEKER_EXTRA_REGIONS {"FPGA", 0x11000000, 0x11010000},
+// RP2350 boards with PSRAM on XIP chip select 1 (e.g. the Pimoroni Pico Plus 2)
+// set PICOPEEKER_PSRAM_SIZE instead, which adds PSRAM and its uncached alias:
+//   #define PICOPEEKER_PSRAM_SIZE 0x800000  // 8MB
+// The XIP cache can be read as SRAM only while the cache is disabled, so that
+// region is left out unless PICOPEEKER_XIP_SRAM is defined.
 #define PICOPEEKER_ROM_START      0x00000000
 #define PICOPEEKER_ROM_END        0x00004000
 #define PICOPEEKER_FLASH_START    0x10000000
+#define PICOPEEKER_SRAM_START     0x20000000
 
 #ifdef PICO_RP2040
 // Raspberry Pi Pico 1 (RP2040) - 264KB SRAM, 2MB Flash
+#ifndef PICOPEEKER_FLASH_END
 #define PICOPEEKER_FLASH_END      0x10200000  // 2MB
-#define PICOPEEKER_SRAM_START     0x20000000
+#endif
+#ifndef PICOPEEKER_SRAM_END
 #define PICOPEEKER_SRAM_END       0x20042000  // 264KB
+#endif
 #else
 // Raspberry Pi Pico 2 (RP2350) - 520KB SRAM, 4MB Flash
+#ifndef PICOPEEKER_FLASH_END
 #define PICOPEEKER_FLASH_END      0x10400000  // 4MB
-#define PICOPEEKER_SRAM_START     0x20000000
+#endif
+#ifndef PICOPEEKER_SRAM_END
 #define PICOPEEKER_SRAM_END       0x20082000  // 520KB
 #endif
+#endif
+
+// Uncached views of flash (and PSRAM), and the cache itself used as SRAM
+#ifdef PICO_RP2040
+#define PICOPEEKER_FLASH_NOCACHE  0x13000000  // XIP_NOCACHE_NOALLOC_BASE
+#define PICOPEEKER_XIP_SRAM_START 0x15000000
+#define PICOPEEKER_XIP_SRAM_END   0x15004000  // 16KB
+#define PICOPEEKER_SRAM_BANKED    0x21000000  // SRAM0-3 without striping
+#define PICOPEEKER_SRAM_BANKED_END 0x21040000
+#else
+#define PICOPEEKER_FLASH_NOCACHE  0x14000000  // XIP_NOCACHE_NOALLOC_BASE
+#define PICOPEEKER_XIP_SRAM_START 0x13ffc000
+#define PICOPEEKER_XIP_SRAM_END   0x14000000  // 16KB
+#endif
+#define PICOPEEKER_FLASH_NOCACHE_END (PICOPEEKER_FLASH_NOCACHE + (PICOPEEKER_FLASH_END - PICOPEEKER_FLASH_START))
+
+#ifndef PICOPEEKER_PSRAM_SIZE
+#ifdef PIMORONI_PICO_PLUS2_PSRAM_CS_PIN
+#define PICOPEEKER_PSRAM_SIZE     0x800000    // 8MB
+#else
+#define PICOPEEKER_PSRAM_SIZE     0
+#endif
+#endif
+#if PICOPEEKER_PSRAM_SIZE > 0
+#ifdef PICO_RP2040
+#error "PICOPEEKER_PSRAM_SIZE is only supported on RP2350"
+#endif
+#define PICOPEEKER_PSRAM_START    0x11000000  // XIP chip select 1
+#define PICOPEEKER_PSRAM_END      (PICOPEEKER_PSRAM_START + PICOPEEKER_PSRAM_SIZE)
+#define PICOPEEKER_PSRAM_NOCACHE  0x15000000
+#define PICOPEEKER_PSRAM_NOCACHE_END (PICOPEEKER_PSRAM_NOCACHE + PICOPEEKER_PSRAM_SIZE)
+#endif
 
 #define PICOPEEKER_PERIPH_START   0x40000000
 #define PICOPEEKER_PERIPH_END     0x60000000
 
+// A readable range of the address space
+typedef struct {
+    const char* name;
+    uint32_t start;
+    uint32_t end;  // Exclusive
+} _picopeeker_region_t;
+
+static const _picopeeker_region_t _picopeeker_regions[] = {
+    {"ROM",         PICOPEEKER_ROM_START,    PICOPEEKER_ROM_END},
+    {"Flash",       PICOPEEKER_FLASH_START,  PICOPEEKER_FLASH_END},
+    {"SRAM",        PICOPEEKER_SRAM_START,   PICOPEEKER_SRAM_END},
+#if PICOPEEKER_PSRAM_SIZE > 0
+    {"PSRAM",       PICOPEEKER_PSRAM_START,  PICOPEEKER_PSRAM_END},
+#endif
+    {"Flash (uncached)", PICOPEEKER_FLASH_NOCACHE, PICOPEEKER_FLASH_NOCACHE_END},
+#if PICOPEEKER_PSRAM_SIZE > 0
+    {"PSRAM (uncached)", PICOPEEKER_PSRAM_NOCACHE, PICOPEEKER_PSRAM_NOCACHE_END},
+#endif
+#ifdef PICOPEEKER_SRAM_BANKED
+    {"SRAM (banked)", PICOPEEKER_SRAM_BANKED, PICOPEEKER_SRAM_BANKED_END},
+#endif
+#ifdef PICOPEEKER_XIP_SRAM
+    {"XIP SRAM",    PICOPEEKER_XIP_SRAM_START, PICOPEEKER_XIP_SRAM_END},
+#endif
+#ifdef PICOPEEKER_EXTRA_REGIONS
+    PICOPEEKER_EXTRA_REGIONS
+#endif
+    {"Peripherals", PICOPEEKER_PERIPH_START, PICOPEEKER_PERIPH_END},
+};
+
+#define PICOPEEKER_REGION_COUNT (sizeof(_picopeeker_regions) / sizeof(_picopeeker_regions[0]))
+
 // Internal state
 static struct {
     bool initialized;
@@ -95,10 +184,21 @@ static struct {
 // Forward declarations
 static void _picopeeker_send_hex_dump(uint32_t address, uint32_t length);
 static void _picopeeker_send_landmarks(void);
-static void _picopeeker_search_region(uint32_t start_addr, uint32_t end_addr,
-                                      const char* region_name, uint8_t* pattern, size_t pattern_len);
-static void _picopeeker_search_memory(uint8_t* pattern, size_t pattern_len,
+static void _picopeeker_send_info(void);
+static bool _picopeeker_parse_pattern(const char* text, uint8_t* pattern, uint8_t* mask, size_t* pattern_len);
+static void _picopeeker_search_region(uint32_t start_addr, uint32_t end_addr, const char* region_name,
+                                      uint8_t* pattern, uint8_t* mask, size_t pattern_len);
+static void _picopeeker_search_memory(uint8_t* pattern, uint8_t* mask, size_t pattern_len,
                                       bool search_flash, bool search_sram);
+static uint32_t _picopeeker_region_end(uint32_t address);
+static void _picopeeker_print_valid_ranges(void);
+static bool _picopeeker_parse_search_args(const char* start_token, const char* end_token, const char* pattern_token,
+                                          uint32_t* start, uint32_t* end,
+                                          uint8_t* pattern, uint8_t* mask, size_t* pattern_len);
+static void _picopeeker_search_page(uint32_t start, uint32_t end, uint8_t* pattern, uint8_t* mask,
+                                    size_t pattern_len, uint32_t max_hits);
+static void _picopeeker_search_count(uint32_t start, uint32_t end, uint8_t* pattern, uint8_t* mask,
+                                     size_t pattern_len);
 static void _picopeeker_parse_command(char* cmd);
 static void _picopeeker_core1_main(void);
 
@@ -157,8 +257,110 @@ static void _picopeeker_send_landmarks(void) {
     fflush(stdout);
 }
 
-static void _picopeeker_search_region(uint32_t start_addr, uint32_t end_addr,
-                                      const char* region_name, uint8_t* pattern, size_t pattern_len) {
+#if LIB_PICO_FLASH && LIB_HARDWARE_FLASH
+// Runs from RAM with Core 0 paused, since flash can't be executed from during the command
+static void __no_inline_not_in_flash_func(_picopeeker_read_jedec_id)(void* param) {
+    uint8_t txbuf[4] = {0x9f, 0, 0, 0};  // JEDEC READ ID
+    flash_do_cmd(txbuf, (uint8_t*)param, sizeof(txbuf));
+}
+#endif
+
+// Returns the flash size in bytes and where it came from ("jedec" or "board")
+static uint32_t _picopeeker_flash_size(const char** source) {
+#if LIB_PICO_FLASH && LIB_HARDWARE_FLASH
+    // Only works if Core 0 allows being paused (see flash_safe_execute_core_init)
+    uint8_t rxbuf[4] = {0};
+    if(flash_safe_execute(_picopeeker_read_jedec_id, rxbuf, 100) == PICO_OK &&
+       rxbuf[3] >= 16 && rxbuf[3] <= 28) {  // Capacity byte is log2(size): 64KB-256MB
+        *source = "jedec";
+        return 1u << rxbuf[3];
+    }
+#endif
+    *source = "board";
+    return PICO_FLASH_SIZE_BYTES;
+}
+
+static void _picopeeker_send_info(void) {
+    const char* flash_size_source;
+    uint32_t flash_size = _picopeeker_flash_size(&flash_size_source);
+
+    printf("INFO:\n");
+#ifdef PICO_RP2040
+    printf("chip=RP2040\n");
+#else
+    printf("chip=RP2350\n");
+#endif
+#if defined(__riscv)
+    printf("arch=riscv\n");
+#else
+    printf("arch=arm\n");
+#endif
+    printf("chip_id=0x%08x\n", (unsigned int)sysinfo_hw->chip_id);
+    printf("sdk=%s\n", PICO_SDK_VERSION_STRING);
+    printf("flash_size=%u\n", (unsigned int)flash_size);
+    printf("flash_size_source=%s\n", flash_size_source);
+    printf("sram_size=%u\n", (unsigned int)(PICOPEEKER_SRAM_END - PICOPEEKER_SRAM_START));
+#if PICOPEEKER_PSRAM_SIZE > 0
+    printf("psram_size=%u\n", (unsigned int)PICOPEEKER_PSRAM_SIZE);
+#endif
+    for(size_t i = 0; i < PICOPEEKER_REGION_COUNT; i++) {
+        printf("region=%s:0x%08x:0x%08x\n", _picopeeker_regions[i].name,
+               (unsigned int)_picopeeker_regions[i].start, (unsigned int)_picopeeker_regions[i].end);
+    }
+
+#if LIB_PICO_UNIQUE_ID
+    // Same ID the SDK uses as the USB serial number, so boards can be told apart
+    char board_id[2 * PICO_UNIQUE_BOARD_ID_SIZE_BYTES + 1];
+    pico_get_unique_board_id_string(board_id, sizeof(board_id));
+    printf("board_id=%s\n", board_id);
+#endif
+
+    printf("END_INFO\n\n");
+    fflush(stdout);
+}
+
+// Parses "HEX" or "HEX/MASK" into pattern bytes and per-byte masks. A mask bit
+// of 0 means "don't care", so a 00 mask byte is a wildcard (?? in the GUI).
+static bool _picopeeker_parse_pattern(const char* text, uint8_t* pattern, uint8_t* mask, size_t* pattern_len) {
+    const char* slash = strchr(text, '/');
+    size_t hex_len = slash ? (size_t)(slash - text) : strlen(text);
+
+    if(hex_len % 2 != 0) {
+        printf("ERROR: Hex pattern must have even number of digits\n");
+        fflush(stdout);
+        return false;
+    }
+
+    size_t len = hex_len / 2;  // Convert to byte length
+    if(len == 0 || len > PICOPEEKER_MAX_PATTERN_SIZE) {
+        printf("ERROR: Pattern length must be 1-%d bytes\n", PICOPEEKER_MAX_PATTERN_SIZE);
+        fflush(stdout);
+        return false;
+    }
+
+    if(slash && strlen(slash + 1) != hex_len) {
+        printf("ERROR: Mask must be the same length as the pattern\n");
+        fflush(stdout);
+        return false;
+    }
+
+    for(size_t i = 0; i < len; i++) {
+        char hex_byte[3] = {text[i*2], text[i*2+1], '\0'};
+        pattern[i] = (uint8_t)strtoul(hex_byte, NULL, 16);
+        mask[i] = 0xFF;
+        if(slash) {
+            char mask_byte[3] = {slash[1 + i*2], slash[2 + i*2], '\0'};
+            mask[i] = (uint8_t)strtoul(mask_byte, NULL, 16);
+        }
+        pattern[i] &= mask[i];  // Ignored bits always compare equal
+    }
+
+    *pattern_len = len;
+    return true;
+}
+
+static void _picopeeker_search_region(uint32_t start_addr, uint32_t end_addr, const char* region_name,
+                                      uint8_t* pattern, uint8_t* mask, size_t pattern_len) {
     uint8_t* ptr = (uint8_t*)start_addr;
     uint32_t total_size = end_addr - start_addr;
     int found_count = 0;
@@ -167,7 +369,13 @@ static void _picopeeker_search_region(uint32_t start_addr, uint32_t end_addr,
     printf("Range: 0x%08x - 0x%08x (%u bytes)\n", start_addr, end_addr, total_size);
     printf("Pattern: ");
     for(size_t i = 0; i < pattern_len; i++) {
-        printf("%02x ", pattern[i]);
+        if(mask[i] == 0x00) {
+            printf("?? ");
+        } else if(mask[i] == 0xFF) {
+            printf("%02x ", pattern[i]);
+        } else {
+            printf("%02x/%02x ", pattern[i], mask[i]);
+        }
     }
     printf("(%zu bytes)\n\n", pattern_len);
     fflush(stdout);
@@ -176,7 +384,7 @@ static void _picopeeker_search_region(uint32_t start_addr, uint32_t end_addr,
     for(uint32_t offset = 0; offset <= total_size - pattern_len; offset++) {
         bool match = true;
         for(size_t i = 0; i < pattern_len; i++) {
-            if(ptr[offset + i] != pattern[i]) {
+            if((ptr[offset + i] & mask[i]) != pattern[i]) {
                 match = false;
                 break;
             }
@@ -201,22 +409,145 @@ static void _picopeeker_search_region(uint32_t start_addr, uint32_t end_addr,
     fflush(stdout);
 }
 
-static void _picopeeker_search_memory(uint8_t* pattern, size_t pattern_len,
+static void _picopeeker_search_memory(uint8_t* pattern, uint8_t* mask, size_t pattern_len,
                                       bool search_flash, bool search_sram) {
     if(search_flash) {
         _picopeeker_search_region(PICOPEEKER_FLASH_START, PICOPEEKER_FLASH_END,
-                                  "FLASH", pattern, pattern_len);
+                                  "FLASH", pattern, mask, pattern_len);
     }
 
     if(search_sram) {
         _picopeeker_search_region(PICOPEEKER_SRAM_START, PICOPEEKER_SRAM_END,
-                                  "SRAM", pattern, pattern_len);
+                                  "SRAM", pattern, mask, pattern_len);
     }
 
     printf("===END===\n");
     fflush(stdout);
 }
 
+// Returns the end of the readable region containing address, or 0 if it is
+// not in one
+static uint32_t _picopeeker_region_end(uint32_t address) {
+    for(size_t i = 0; i < PICOPEEKER_REGION_COUNT; i++) {
+        if(address >= _picopeeker_regions[i].start && address < _picopeeker_regions[i].end) {
+            return _picopeeker_regions[i].end;
+        }
+    }
+    return 0;
+}
+
+static void _picopeeker_print_valid_ranges(void) {
+    printf("Valid ranges:\n");
+    for(size_t i = 0; i < PICOPEEKER_REGION_COUNT; i++) {
+        const _picopeeker_region_t* region = &_picopeeker_regions[i];
+        int pad = 16 - (int)strlen(region->name);
+        printf("  %s:%*s 0x%08x-0x%08x\n", region->name, pad > 0 ? pad : 0, "",
+               (unsigned int)region->start, (unsigned int)(region->end - 1));
+    }
+}
+
+// Parses the range and pattern of SEARCHRANGE and SEARCHPAGE. The range must
+// start in a readable region and is clamped to its end.
+static bool _picopeeker_parse_search_args(const char* start_token, const char* end_token, const char* pattern_token,
+                                          uint32_t* start, uint32_t* end,
+                                          uint8_t* pattern, uint8_t* mask, size_t* pattern_len) {
+    *start = strtoul(start_token, NULL, 16);
+    *end = strtoul(end_token, NULL, 16);
+
+    uint32_t region_end = _picopeeker_region_end(*start);
+    if(region_end == 0) {
+        printf("ERROR: Start address out of valid range\n");
+        _picopeeker_print_valid_ranges();
+        fflush(stdout);
+        return false;
+    }
+    if(*end <= *start) {
+        printf("ERROR: End address must be after the start address\n");
+        fflush(stdout);
+        return false;
+    }
+    // A range may not cross into the next region
+    if(*end > region_end) {
+        printf("WARNING: End clamped from 0x%08x to 0x%08x to stay within region bounds\n",
+               *end, region_end);
+        fflush(stdout);
+        *end = region_end;
+    }
+
+    if(!_picopeeker_parse_pattern(pattern_token, pattern, mask, pattern_len)) {
+        return false;
+    }
+    if(*end - *start < *pattern_len) {
+        printf("ERROR: Range is shorter than the pattern\n");
+        fflush(stdout);
+        return false;
+    }
+    return true;
+}
+
+// Reports up to max_hits matches starting at start, then either "MORE: 0xNEXT"
+// (the address to resume from) or "DONE". Unlike SEARCH there is no overall
+// limit: the host keeps asking for pages until it sees DONE.
+static void _picopeeker_search_page(uint32_t start, uint32_t end, uint8_t* pattern, uint8_t* mask,
+                                    size_t pattern_len, uint32_t max_hits) {
+    uint8_t* ptr = (uint8_t*)start;
+    uint32_t total_size = end - start;
+    uint32_t found_count = 0;
+
+    for(uint32_t offset = 0; offset <= total_size - pattern_len; offset++) {
+        bool match = true;
+        for(size_t i = 0; i < pattern_len; i++) {
+            if((ptr[offset + i] & mask[i]) != pattern[i]) {
+                match = false;
+                break;
+            }
+        }
+        if(!match) {
+            continue;
+        }
+
+        printf("FOUND: 0x%08x\n", start + offset);
+        fflush(stdout);
+        found_count++;
+        if(found_count >= max_hits && offset + 1 <= total_size - pattern_len) {
+            printf("MORE: 0x%08x\n", start + offset + 1);
+            printf("===END===\n");
+            fflush(stdout);
+            return;
+        }
+    }
+
+    printf("DONE\n");
+    printf("===END===\n");
+    fflush(stdout);
+}
+
+// Reports how many matches [start, end) has, without listing them, so the
+// host can show a total while it pages through the hits with SEARCHPAGE.
+static void _picopeeker_search_count(uint32_t start, uint32_t end, uint8_t* pattern, uint8_t* mask,
+                                     size_t pattern_len) {
+    uint8_t* ptr = (uint8_t*)start;
+    uint32_t total_size = end - start;
+    uint32_t found_count = 0;
+
+    for(uint32_t offset = 0; offset <= total_size - pattern_len; offset++) {
+        bool match = true;
+        for(size_t i = 0; i < pattern_len; i++) {
+            if((ptr[offset + i] & mask[i]) != pattern[i]) {
+                match = false;
+                break;
+            }
+        }
+        if(match) {
+            found_count++;
+        }
+    }
+
+    printf("COUNT: %u\n", (unsigned int)found_count);
+    printf("===END===\n");
+    fflush(stdout);
+}
+
 static void _picopeeker_parse_command(char* cmd) {
     // Handle LANDMARKS command
     if(strcmp(cmd, "LANDMARKS") == 0) {
@@ -224,6 +555,12 @@ static void _picopeeker_parse_command(char* cmd) {
         return;
     }
 
+    // Handle INFO command
+    if(strcmp(cmd, "INFO") == 0) {
+        _picopeeker_send_info();
+        return;
+    }
+
     // Save original cmd for later parsing
     char cmd_copy[PICOPEEKER_CMD_BUFFER_SIZE];
     strncpy(cmd_copy, cmd, PICOPEEKER_CMD_BUFFER_SIZE - 1);
@@ -241,70 +578,140 @@ static void _picopeeker_parse_command(char* cmd) {
         token = strtok(NULL, ":");
         if(token == NULL) {
             printf("ERROR: Missing search pattern\n");
-            printf("Usage: SEARCH:HEXPATTERN\n");
+            printf("Usage: SEARCH:HEXPATTERN[/MASK]\n");
             printf("Example: SEARCH:DEADBEEF\n");
             fflush(stdout);
             return;
         }
 
-        // Parse hex pattern
-        size_t pattern_len = strlen(token);
-        if(pattern_len % 2 != 0) {
-            printf("ERROR: Hex pattern must have even number of digits\n");
+        uint8_t pattern[PICOPEEKER_MAX_PATTERN_SIZE];
+        uint8_t mask[PICOPEEKER_MAX_PATTERN_SIZE];
+        size_t pattern_len;
+        if(!_picopeeker_parse_pattern(token, pattern, mask, &pattern_len)) {
+            return;
+        }
+
+        _picopeeker_search_memory(pattern, mask, pattern_len, false, true);
+        return;
+    }
+
+    // Handle SEARCHFLASH command
+    if(strcmp(token, "SEARCHFLASH") == 0) {
+        token = strtok(NULL, ":");
+        if(token == NULL) {
+            printf("ERROR: Missing search pattern\n");
+            printf("Usage: SEARCHFLASH:HEXPATTERN[/MASK]\n");
+            printf("Example: SEARCHFLASH:48656C6C6F (search for 'Hello')\n");
             fflush(stdout);
             return;
         }
 
-        pattern_len /= 2;  // Convert to byte length
-        if(pattern_len == 0 || pattern_len > PICOPEEKER_MAX_PATTERN_SIZE) {
-            printf("ERROR: Pattern length must be 1-%d bytes\n", PICOPEEKER_MAX_PATTERN_SIZE);
+        uint8_t pattern[PICOPEEKER_MAX_PATTERN_SIZE];
+        uint8_t mask[PICOPEEKER_MAX_PATTERN_SIZE];
+        size_t pattern_len;
+        if(!_picopeeker_parse_pattern(token, pattern, mask, &pattern_len)) {
+            return;
+        }
+
+        _picopeeker_search_memory(pattern, mask, pattern_len, true, false);
+        return;
+    }
+
+    // Handle SEARCHRANGE command (any readable range, end exclusive)
+    if(strcmp(token, "SEARCHRANGE") == 0) {
+        char* start_token = strtok(NULL, ":");
+        char* end_token = strtok(NULL, ":");
+        token = strtok(NULL, ":");
+        if(start_token == NULL || end_token == NULL || token == NULL) {
+            printf("ERROR: Missing search range or pattern\n");
+            printf("Usage: SEARCHRANGE:0xSTART:0xEND:HEXPATTERN[/MASK]\n");
+            printf("Example: SEARCHRANGE:0x00000000:0x00004000:4D75 (search the boot ROM)\n");
+            printf("===END===\n");
             fflush(stdout);
             return;
         }
 
+        uint32_t start, end;
         uint8_t pattern[PICOPEEKER_MAX_PATTERN_SIZE];
-        for(size_t i = 0; i < pattern_len; i++) {
-            char hex_byte[3] = {token[i*2], token[i*2+1], '\0'};
-            pattern[i] = (uint8_t)strtoul(hex_byte, NULL, 16);
+        uint8_t mask[PICOPEEKER_MAX_PATTERN_SIZE];
+        size_t pattern_len;
+        if(!_picopeeker_parse_search_args(start_token, end_token, token,
+                                          &start, &end, pattern, mask, &pattern_len)) {
+            printf("===END===\n");
+            fflush(stdout);
+            return;
         }
 
-        _picopeeker_search_memory(pattern, pattern_len, false, true);
+        _picopeeker_search_region(start, end, "RANGE", pattern, mask, pattern_len);
+        printf("===END===\n");
+        fflush(stdout);
         return;
     }
 
-    // Handle SEARCHFLASH command
-    if(strcmp(token, "SEARCHFLASH") == 0) {
+    // Handle SEARCHPAGE command (resumable search, COUNT hits at a time)
+    if(strcmp(token, "SEARCHPAGE") == 0) {
+        char* start_token = strtok(NULL, ":");
+        char* end_token = strtok(NULL, ":");
+        char* count_token = strtok(NULL, ":");
         token = strtok(NULL, ":");
-        if(token == NULL) {
-            printf("ERROR: Missing search pattern\n");
-            printf("Usage: SEARCHFLASH:HEXPATTERN\n");
-            printf("Example: SEARCHFLASH:48656C6C6F (search for 'Hello')\n");
+        if(start_token == NULL || end_token == NULL || count_token == NULL || token == NULL) {
+            printf("ERROR: Missing search range, count or pattern\n");
+            printf("Usage: SEARCHPAGE:0xSTART:0xEND:COUNT:HEXPATTERN[/MASK]\n");
+            printf("Example: SEARCHPAGE:0x20000000:0x20082000:100:2A000000\n");
+            printf("===END===\n");  // Errors end like results, so the host doesn't wait for a timeout
+            fflush(stdout);
+            return;
+        }
+
+        uint32_t max_hits = atoi(count_token);
+        if(max_hits == 0) {
+            printf("ERROR: Count must be at least 1\n");
+            printf("===END===\n");
             fflush(stdout);
             return;
         }
 
-        // Parse hex pattern
-        size_t pattern_len = strlen(token);
-        if(pattern_len % 2 != 0) {
-            printf("ERROR: Hex pattern must have even number of digits\n");
+        uint32_t start, end;
+        uint8_t pattern[PICOPEEKER_MAX_PATTERN_SIZE];
+        uint8_t mask[PICOPEEKER_MAX_PATTERN_SIZE];
+        size_t pattern_len;
+        if(!_picopeeker_parse_search_args(start_token, end_token, token,
+                                          &start, &end, pattern, mask, &pattern_len)) {
+            printf("===END===\n");
             fflush(stdout);
             return;
         }
 
-        pattern_len /= 2;  // Convert to byte length
-        if(pattern_len == 0 || pattern_len > PICOPEEKER_MAX_PATTERN_SIZE) {
-            printf("ERROR: Pattern length must be 1-%d bytes\n", PICOPEEKER_MAX_PATTERN_SIZE);
+        _picopeeker_search_page(start, end, pattern, mask, pattern_len, max_hits);
+        return;
+    }
+
+    // Handle SEARCHCOUNT command (number of matches in a range)
+    if(strcmp(token, "SEARCHCOUNT") == 0) {
+        char* start_token = strtok(NULL, ":");
+        char* end_token = strtok(NULL, ":");
+        token = strtok(NULL, ":");
+        if(start_token == NULL || end_token == NULL || token == NULL) {
+            printf("ERROR: Missing search range or pattern\n");
+            printf("Usage: SEARCHCOUNT:0xSTART:0xEND:HEXPATTERN[/MASK]\n");
+            printf("Example: SEARCHCOUNT:0x20000000:0x20082000:2A000000\n");
+            printf("===END===\n");
             fflush(stdout);
             return;
         }
 
+        uint32_t start, end;
         uint8_t pattern[PICOPEEKER_MAX_PATTERN_SIZE];
-        for(size_t i = 0; i < pattern_len; i++) {
-            char hex_byte[3] = {token[i*2], token[i*2+1], '\0'};
-            pattern[i] = (uint8_t)strtoul(hex_byte, NULL, 16);
+        uint8_t mask[PICOPEEKER_MAX_PATTERN_SIZE];
+        size_t pattern_len;
+        if(!_picopeeker_parse_search_args(start_token, end_token, token,
+                                          &start, &end, pattern, mask, &pattern_len)) {
+            printf("===END===\n");
+            fflush(stdout);
+            return;
         }
 
-        _picopeeker_search_memory(pattern, pattern_len, true, false);
+        _picopeeker_search_count(start, end, pattern, mask, pattern_len);
         return;
     }
 
@@ -342,23 +749,10 @@ static void _picopeeker_parse_command(char* cmd) {
 
     // Validate address range and clamp length
     uint32_t max_length = length;
-    uint32_t region_end = 0;
-
-    if(address >= PICOPEEKER_ROM_START && address < PICOPEEKER_ROM_END) {
-        region_end = PICOPEEKER_ROM_END;
-    } else if(address >= PICOPEEKER_FLASH_START && address < PICOPEEKER_FLASH_END) {
-        region_end = PICOPEEKER_FLASH_END;
-    } else if(address >= PICOPEEKER_SRAM_START && address < PICOPEEKER_SRAM_END) {
-        region_end = PICOPEEKER_SRAM_END;
-    } else if(address >= PICOPEEKER_PERIPH_START && address < PICOPEEKER_PERIPH_END) {
-        region_end = PICOPEEKER_PERIPH_END;
-    } else {
+    uint32_t region_end = _picopeeker_region_end(address);
+    if(region_end == 0) {
         printf("ERROR: Address out of valid range\n");
-        printf("Valid ranges:\n");
-        printf("  ROM:         0x%08x-0x%08x\n", PICOPEEKER_ROM_START, PICOPEEKER_ROM_END - 1);
-        printf("  Flash:       0x%08x-0x%08x\n", PICOPEEKER_FLASH_START, PICOPEEKER_FLASH_END - 1);
-        printf("  SRAM:        0x%08x-0x%08x\n", PICOPEEKER_SRAM_START, PICOPEEKER_SRAM_END - 1);
-        printf("  Peripherals: 0x%08x-0x%08x\n", PICOPEEKER_PERIPH_START, PICOPEEKER_PERIPH_END - 1);
+        _picopeeker_print_valid_ranges();
         fflush(stdout);
         return;
     }
@@ -383,13 +777,18 @@ static void _picopeeker_core1_main(void) {
     printf("PicoPeeker ready!\n");
     printf("Commands:\n");
     printf("  READ:0xADDRESS:LENGTH   - Read memory\n");
-    printf("  SEARCH:HEXPATTERN       - Search SRAM for hex pattern\n");
-    printf("  SEARCHFLASH:HEXPATTERN  - Search Flash for hex pattern\n");
+    printf("  SEARCH:HEX[/MASK]       - Search SRAM for hex pattern (mask bits 0 = any)\n");
+    printf("  SEARCHFLASH:HEX[/MASK]  - Search Flash for hex pattern\n");
+    printf("  SEARCHRANGE:0xSTART:0xEND:HEX[/MASK] - Search an address range\n");
+    printf("  SEARCHPAGE:0xSTART:0xEND:COUNT:HEX[/MASK] - Next COUNT matches from START\n");
+    printf("  SEARCHCOUNT:0xSTART:0xEND:HEX[/MASK] - Number of matches in a range\n");
     printf("  LANDMARKS               - Show memory landmarks\n");
+    printf("  INFO                    - Identify the chip, SDK and memory sizes\n");
     printf("Examples:\n");
     printf("  READ:0x20000000:256\n");
     printf("  SEARCH:2A000000 (search for int 42 in SRAM)\n");
-    printf("  SEARCHFLASH:48656C6C6F (search for 'Hello' in Flash)\n\n");
+    printf("  SEARCHFLASH:48656C6C6F (search for 'Hello' in Flash)\n");
+    printf("  SEARCH:DEAD00EF/FFFF00FF (DE AD ?? EF in SRAM)\n\n");
     fflush(stdout);
 
     // Send landmarks on startup